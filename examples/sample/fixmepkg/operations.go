@@ -0,0 +1,116 @@
+package fixmepkg
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/dmage/graphql/pkg/graphql/client"
+)
+
+type GetRepositoryResponseRepositoryPullRequestAuthor struct {
+	Typename string `json:"__typename"`
+
+	field_login *string `json:"login"`
+	asUser      *GetRepositoryResponseRepositoryPullRequestAuthorAsUser
+}
+
+func (o GetRepositoryResponseRepositoryPullRequestAuthor) Login() *string {
+	return o.field_login
+}
+
+// AsUser returns the selection for the "User" inline fragment and true
+// if the underlying object had __typename "User".
+func (o *GetRepositoryResponseRepositoryPullRequestAuthor) AsUser() (*GetRepositoryResponseRepositoryPullRequestAuthorAsUser, bool) {
+	return o.asUser, o.asUser != nil
+}
+
+func (o *GetRepositoryResponseRepositoryPullRequestAuthor) UnmarshalJSON(data []byte) error {
+	var v struct {
+		Typename    string  `json:"__typename"`
+		field_login *string `json:"login"`
+	}
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		return err
+	}
+	o.Typename = v.Typename
+	o.field_login = v.field_login
+	switch v.Typename {
+	case "User":
+		var as GetRepositoryResponseRepositoryPullRequestAuthorAsUser
+		err = json.Unmarshal(data, &as)
+		if err != nil {
+			return err
+		}
+		o.asUser = &as
+	}
+	return nil
+}
+
+type GetRepositoryResponseRepositoryPullRequestAuthorAsUser struct {
+	field_company *string `json:"company"`
+}
+
+func (o GetRepositoryResponseRepositoryPullRequestAuthorAsUser) Company() *string {
+	return o.field_company
+}
+
+type GetRepositoryResponseRepositoryPullRequest struct {
+	field_author *GetRepositoryResponseRepositoryPullRequestAuthor `json:"author"`
+}
+
+func (o GetRepositoryResponseRepositoryPullRequest) Author() *GetRepositoryResponseRepositoryPullRequestAuthor {
+	return o.field_author
+}
+
+type GetRepositoryResponseRepository struct {
+	field_description *string `json:"description"`
+
+	field_pullRequest *GetRepositoryResponseRepositoryPullRequest `json:"pullRequest"`
+}
+
+func (o GetRepositoryResponseRepository) Description() *string {
+	return o.field_description
+}
+
+func (o GetRepositoryResponseRepository) PullRequest() *GetRepositoryResponseRepositoryPullRequest {
+	return o.field_pullRequest
+}
+
+type GetRepositoryResponse struct {
+	field_repository *GetRepositoryResponseRepository `json:"repository"`
+}
+
+func (o GetRepositoryResponse) Repository() *GetRepositoryResponseRepository {
+	return o.field_repository
+}
+
+type GetRepositoryVariables struct {
+	Owner  string `json:"owner"`
+	Name   string `json:"name"`
+	Number int32  `json:"number"`
+}
+
+// DoGetRepository executes the "GetRepository" query operation through c and decodes its
+// response into a GetRepositoryResponse.
+func DoGetRepository(ctx context.Context, c *client.Client, variables GetRepositoryVariables) (*GetRepositoryResponse, error) {
+	var resp GetRepositoryResponse
+	err := c.Do(ctx, `query GetRepository($owner: String!, $name: String!, $number: Int!) {
+	repository(owner: $owner, name: $name) {
+		description
+		pullRequest(number: $number) {
+			author {
+				login
+				... on User {
+					company
+				}
+			}
+		}
+	}
+}
+`, variables, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,49 @@
+// Command sample is a runnable example of calling the GitHub GraphQL API
+// through pkg/graphql/client and a generated operation function. It lives
+// in its own package so that the root module (gen.go et al.) can be built
+// and tested without also building a sample that depends on generated
+// code of its own.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"golang.org/x/oauth2"
+
+	"github.com/dmage/graphql/examples/sample/fixmepkg"
+	"github.com/dmage/graphql/pkg/graphql/client"
+)
+
+//go:generate go run github.com/dmage/graphql -schema schema.graphql -query query.graphql
+
+func main() {
+	src := oauth2.StaticTokenSource(
+		&oauth2.Token{AccessToken: os.Getenv("GITHUB_TOKEN")},
+	)
+	httpClient := oauth2.NewClient(context.Background(), src)
+
+	c := &client.Client{
+		HTTPClient: httpClient,
+		Endpoint:   "https://api.github.com/graphql",
+	}
+
+	resp, err := fixmepkg.DoGetRepository(context.Background(), c, fixmepkg.GetRepositoryVariables{
+		Owner:  "openshift",
+		Name:   "origin",
+		Number: 14521,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("%#+v", resp)
+	log.Println(*resp.Repository().Description())
+
+	author := resp.Repository().PullRequest().Author()
+	log.Println(*author.Login())
+	if user, ok := author.AsUser(); ok {
+		log.Println(*user.Company())
+	}
+}
@@ -3,16 +3,36 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"flag"
 	"fmt"
+	"go/format"
+	"io/ioutil"
 	"log"
 	"os"
 	"path"
+	"sort"
 	"strings"
 
+	"github.com/dmage/graphql/pkg/query"
 	"github.com/dmage/graphql/pkg/schema"
+	"github.com/dmage/graphql/pkg/schema/sdl"
 	"github.com/dmage/graphql/pkg/schema/typekind"
 )
 
+// queryFiles collects the -query flags; it may be given more than once to
+// generate typed response structs for operations spread across several
+// .graphql files.
+type queryFiles []string
+
+func (q *queryFiles) String() string {
+	return strings.Join(*q, ",")
+}
+
+func (q *queryFiles) Set(v string) error {
+	*q = append(*q, v)
+	return nil
+}
+
 type FieldConfig struct {
 	Name   string
 	Import string
@@ -46,6 +66,24 @@ type ScalarConfig struct {
 	// File into which the definition of a scalar should be written.
 	// Should be empty for predeclared names.
 	File string
+
+	// Wire is the JSON value kind this scalar is encoded as: "string",
+	// "number", or "object". Defaults to "string". Only consulted when
+	// Unmarshaler (or Marshaler) is set.
+	Wire string
+
+	// Marshaler and Unmarshaler are fully-qualified function names (e.g.
+	// "github.com/dmage/graphql/pkg/scalars.UnmarshalTime") converting
+	// between the Wire JSON kind and Type. When either is set together
+	// with Type, no `type X Y` wrapper is generated and Type is used
+	// directly as the field's Go type, unless Name is also set, in which
+	// case Name is used as the field's Go type. renderObject's generated UnmarshalJSON
+	// decodes response fields through Unmarshaler instead of assigning
+	// the wire value straight into the field, and renderInputObject's
+	// generated MarshalJSON encodes request fields through Marshaler the
+	// same way.
+	Marshaler   string
+	Unmarshaler string
 }
 
 type Config struct {
@@ -149,6 +187,15 @@ func getNameNullable(config *Config, typ schema.Type, nullable bool) string {
 		if cfg.Name != "" {
 			return prefix + cfg.Name
 		}
+
+		if (cfg.Unmarshaler != "" || cfg.Marshaler != "") && cfg.Type != "" {
+			if strings.HasPrefix(cfg.Type, "*") {
+				// Type is already nil-able on its own (e.g. *url.URL,
+				// *big.Int); don't wrap it in another pointer.
+				return cfg.Type
+			}
+			return prefix + cfg.Type
+		}
 	case typekind.NonNull:
 		return getNameNullable(config, *typ.OfType, false)
 	case typekind.List:
@@ -184,6 +231,11 @@ func getFile(config *Config, typ schema.Type) string {
 	switch typ.Kind {
 	case typekind.Scalar:
 		cfg := getScalarConfig(config, *typ.Name)
+		if (cfg.Unmarshaler != "" || cfg.Marshaler != "") && cfg.Type != "" {
+			// Maps directly onto an existing Go type; there is no
+			// `type X Y` declaration to generate.
+			return ""
+		}
 		if cfg.File != "" {
 			return cfg.File
 		}
@@ -198,16 +250,110 @@ func getFile(config *Config, typ schema.Type) string {
 	case typekind.Union:
 		return "unions.go"
 	case typekind.InputObject:
-		return "" // FIXME
+		return "inputs.go"
 	}
 	panic(fmt.Errorf("don't know how to get file for %#+v", typ))
 }
 
+// typeSortName returns the key used to give generated output a stable
+// order regardless of the order types arrived in from introspection JSON
+// or SDL source.
+func typeSortName(typ schema.Type) string {
+	if typ.Name == nil {
+		return ""
+	}
+	return *typ.Name
+}
+
 func renderComment(prefix, s string) string {
 	return fmt.Sprintf("%s%s\n", prefix, strings.Replace(s, "\n", "\n"+prefix, -1))
 }
 
+// scalarHookConfig reports the ScalarConfig for typ's named scalar, if the
+// scalar has an Unmarshaler configured. NonNull is unwrapped so that e.g.
+// a required DateTime! field is still recognized.
+func scalarHookConfig(config *Config, typ schema.Type) (ScalarConfig, bool) {
+	if typ.Kind == typekind.NonNull {
+		return scalarHookConfig(config, *typ.OfType)
+	}
+	if typ.Kind != typekind.Scalar {
+		return ScalarConfig{}, false
+	}
+	cfg := getScalarConfig(config, *typ.Name)
+	if cfg.Unmarshaler == "" {
+		return ScalarConfig{}, false
+	}
+	return cfg, true
+}
+
+// scalarMarshalConfig reports the ScalarConfig for typ's named scalar, if
+// the scalar has a Marshaler configured. NonNull is unwrapped so that e.g.
+// a required URI! field is still recognized.
+func scalarMarshalConfig(config *Config, typ schema.Type) (ScalarConfig, bool) {
+	if typ.Kind == typekind.NonNull {
+		return scalarMarshalConfig(config, *typ.OfType)
+	}
+	if typ.Kind != typekind.Scalar {
+		return ScalarConfig{}, false
+	}
+	cfg := getScalarConfig(config, *typ.Name)
+	if cfg.Marshaler == "" {
+		return ScalarConfig{}, false
+	}
+	return cfg, true
+}
+
+// wireGoType returns the Go type used to decode a scalar's wire
+// representation prior to conversion by its Unmarshaler.
+func wireGoType(wire string) string {
+	switch wire {
+	case "number":
+		return "float64"
+	case "object":
+		return "json.RawMessage"
+	default:
+		return "string"
+	}
+}
+
+// splitQualifiedFunc splits a fully-qualified function name such as
+// "github.com/dmage/graphql/pkg/scalars.UnmarshalTime" into the import
+// path to add and the package-qualified expression to call.
+func splitQualifiedFunc(qualified string) (importPath, expr string) {
+	pkgPath := qualified
+	fn := ""
+	if i := strings.LastIndex(qualified, "."); i >= 0 {
+		pkgPath = qualified[:i]
+		fn = qualified[i+1:]
+	}
+	pkgName := pkgPath
+	if i := strings.LastIndex(pkgPath, "/"); i >= 0 {
+		pkgName = pkgPath[i+1:]
+	}
+	return pkgPath, pkgName + "." + fn
+}
+
 func renderObject(config *Config, typ schema.Type) ([]string, string) {
+	imports := []string{"encoding/json"}
+	addImport := func(im string) {
+		if im == "" {
+			return
+		}
+		for _, x := range imports {
+			if x == im {
+				return
+			}
+		}
+		imports = append(imports, im)
+	}
+	for _, field := range typ.Fields {
+		if cfg, ok := scalarHookConfig(config, field.Type); ok {
+			addImport(cfg.Import)
+			importPath, _ := splitQualifiedFunc(cfg.Unmarshaler)
+			addImport(importPath)
+		}
+	}
+
 	var buf bytes.Buffer
 	if typ.Description != nil {
 		buf.WriteString(renderComment("// ", *typ.Description))
@@ -236,6 +382,12 @@ func renderObject(config *Config, typ schema.Type) ([]string, string) {
 	for _, field := range typ.Fields {
 		if field.Type.Kind == typekind.Interface {
 			fmt.Fprintf(&buf, "\t\tfield_%s json.RawMessage `json:\"%s\"`\n", field.Name, field.Name)
+		} else if cfg, ok := scalarHookConfig(config, field.Type); ok {
+			wireType := wireGoType(cfg.Wire)
+			if field.Type.Kind != typekind.NonNull {
+				wireType = "*" + wireType
+			}
+			fmt.Fprintf(&buf, "\t\tfield_%s %s `json:\"%s\"`\n", field.Name, wireType, field.Name)
 		} else {
 			fieldType := getFieldType(config, typ, field)
 			fmt.Fprintf(&buf, "\t\tfield_%s %s `json:\"%s\"`\n", field.Name, fieldType, field.Name)
@@ -253,13 +405,38 @@ func renderObject(config *Config, typ schema.Type) ([]string, string) {
 			fmt.Fprintf(&buf, "\tif err != nil {\n")
 			fmt.Fprintf(&buf, "\t\treturn err\n")
 			fmt.Fprintf(&buf, "\t}\n")
+		} else if cfg, ok := scalarHookConfig(config, field.Type); ok {
+			_, expr := splitQualifiedFunc(cfg.Unmarshaler)
+			if field.Type.Kind != typekind.NonNull {
+				fmt.Fprintf(&buf, "\tif v.field_%s != nil {\n", field.Name)
+				if getFieldType(config, typ, field) == "*"+cfg.Type {
+					// The field points at the Unmarshaler's result type
+					// (e.g. *time.Time for time.Time); take its address.
+					fmt.Fprintf(&buf, "\t\tx, err := %s(*v.field_%s)\n", expr, field.Name)
+					fmt.Fprintf(&buf, "\t\tif err != nil {\n")
+					fmt.Fprintf(&buf, "\t\t\treturn err\n")
+					fmt.Fprintf(&buf, "\t\t}\n")
+					fmt.Fprintf(&buf, "\t\to.field_%s = &x\n", field.Name)
+				} else {
+					fmt.Fprintf(&buf, "\t\to.field_%s, err = %s(*v.field_%s)\n", field.Name, expr, field.Name)
+					fmt.Fprintf(&buf, "\t\tif err != nil {\n")
+					fmt.Fprintf(&buf, "\t\t\treturn err\n")
+					fmt.Fprintf(&buf, "\t\t}\n")
+				}
+				fmt.Fprintf(&buf, "\t}\n")
+			} else {
+				fmt.Fprintf(&buf, "\to.field_%s, err = %s(v.field_%s)\n", field.Name, expr, field.Name)
+				fmt.Fprintf(&buf, "\tif err != nil {\n")
+				fmt.Fprintf(&buf, "\t\treturn err\n")
+				fmt.Fprintf(&buf, "\t}\n")
+			}
 		} else {
 			fmt.Fprintf(&buf, "\to.field_%s = v.field_%s\n", field.Name, field.Name)
 		}
 	}
 	fmt.Fprintf(&buf, "\treturn nil\n")
 	fmt.Fprintf(&buf, "}\n")
-	return []string{"encoding/json"}, buf.String()
+	return imports, buf.String()
 }
 
 func renderScalar(config *Config, typ schema.Type) string {
@@ -278,6 +455,153 @@ func renderScalar(config *Config, typ schema.Type) string {
 	return buf.String()
 }
 
+// renderInputObject emits a Go struct for an INPUT_OBJECT type. Fields get
+// exported names directly (no field_x/accessor pair, unlike renderObject):
+// callers construct input structs themselves, they don't decode them.
+//
+// A field with a default value is rendered as a plain (non-pointer) Go
+// value with `json:",omitempty"`, so leaving it at its Go zero value omits
+// it from the request and lets the server apply the default. This does not
+// hold for a field of input object type: its Go value is a struct, which
+// omitempty never omits. A nullable field with no default is rendered as a
+// pointer with a plain tag (no omitempty), so leaving it nil sends an
+// explicit null. A non-null field with no default is required and
+// rendered as a plain value.
+//
+// If any field's scalar has a Marshaler configured, a MarshalJSON method is
+// also generated that encodes those fields through it instead of letting
+// encoding/json marshal the Go scalar type directly; without it, a type
+// like *url.URL would serialize as its struct fields instead of the wire
+// string the server expects.
+func renderInputObject(config *Config, typ schema.Type) ([]string, string) {
+	name := getNameNullable(config, typ, false)
+
+	type inputField struct {
+		field        schema.InputValue
+		goName       string
+		fieldType    string
+		jsonTag      string
+		pointerField bool
+	}
+
+	var fields []inputField
+	for _, f := range typ.InputFields {
+		nullable := f.Type.Kind != typekind.NonNull
+		hasDefault := f.DefaultValue != nil
+
+		var fieldType, jsonTag string
+		switch {
+		case hasDefault:
+			fieldType = getNameNullable(config, f.Type, false)
+			jsonTag = f.Name + ",omitempty"
+		case nullable:
+			fieldType = getNameNullable(config, f.Type, true)
+			jsonTag = f.Name
+		default:
+			fieldType = getNameNullable(config, f.Type, false)
+			jsonTag = f.Name
+		}
+		fields = append(fields, inputField{
+			field:        f,
+			goName:       strings.Title(f.Name),
+			fieldType:    fieldType,
+			jsonTag:      jsonTag,
+			pointerField: nullable && !hasDefault,
+		})
+	}
+
+	var imports []string
+	addImport := func(im string) {
+		if im == "" {
+			return
+		}
+		for _, x := range imports {
+			if x == im {
+				return
+			}
+		}
+		imports = append(imports, im)
+	}
+
+	hasHook := false
+	for _, f := range fields {
+		if cfg, ok := scalarMarshalConfig(config, f.field.Type); ok {
+			hasHook = true
+			addImport(cfg.Import)
+			importPath, _ := splitQualifiedFunc(cfg.Marshaler)
+			addImport(importPath)
+		}
+	}
+
+	var buf bytes.Buffer
+	if typ.Description != nil {
+		buf.WriteString(renderComment("// ", *typ.Description))
+	}
+	fmt.Fprintf(&buf, "type %s struct {\n", name)
+	for i, f := range fields {
+		if i != 0 {
+			buf.WriteString("\n")
+		}
+		if f.field.Description != nil {
+			buf.WriteString(renderComment("\t// ", *f.field.Description))
+		}
+		fmt.Fprintf(&buf, "\t%s %s `json:%q`\n", f.goName, f.fieldType, f.jsonTag)
+	}
+	buf.WriteString("}\n")
+
+	if hasHook {
+		addImport("encoding/json")
+		fmt.Fprintf(&buf, "\nfunc (o %s) MarshalJSON() ([]byte, error) {\n", name)
+		fmt.Fprintf(&buf, "\tvar v struct {\n")
+		for _, f := range fields {
+			if cfg, ok := scalarMarshalConfig(config, f.field.Type); ok {
+				wireType := wireGoType(cfg.Wire)
+				if f.pointerField {
+					wireType = "*" + wireType
+				}
+				fmt.Fprintf(&buf, "\t\t%s %s `json:%q`\n", f.goName, wireType, f.jsonTag)
+			} else {
+				fmt.Fprintf(&buf, "\t\t%s %s `json:%q`\n", f.goName, f.fieldType, f.jsonTag)
+			}
+		}
+		fmt.Fprintf(&buf, "\t}\n")
+		for _, f := range fields {
+			if cfg, ok := scalarMarshalConfig(config, f.field.Type); ok {
+				_, expr := splitQualifiedFunc(cfg.Marshaler)
+				if f.pointerField {
+					fmt.Fprintf(&buf, "\tif o.%s != nil {\n", f.goName)
+					arg := "*o." + f.goName
+					if f.fieldType == cfg.Type {
+						// Type is already a pointer (e.g. *url.URL) and was
+						// not wrapped in another one; pass it as is.
+						arg = "o." + f.goName
+					}
+					fmt.Fprintf(&buf, "\t\tw, err := %s(%s)\n", expr, arg)
+					fmt.Fprintf(&buf, "\t\tif err != nil {\n")
+					fmt.Fprintf(&buf, "\t\t\treturn nil, err\n")
+					fmt.Fprintf(&buf, "\t\t}\n")
+					fmt.Fprintf(&buf, "\t\tv.%s = &w\n", f.goName)
+					fmt.Fprintf(&buf, "\t}\n")
+				} else {
+					fmt.Fprintf(&buf, "\t{\n")
+					fmt.Fprintf(&buf, "\t\tw, err := %s(o.%s)\n", expr, f.goName)
+					fmt.Fprintf(&buf, "\t\tif err != nil {\n")
+					fmt.Fprintf(&buf, "\t\t\treturn nil, err\n")
+					fmt.Fprintf(&buf, "\t\t}\n")
+					fmt.Fprintf(&buf, "\t\tv.%s = w\n", f.goName)
+					fmt.Fprintf(&buf, "\t}\n")
+				}
+			} else {
+				fmt.Fprintf(&buf, "\tv.%s = o.%s\n", f.goName, f.goName)
+			}
+		}
+		fmt.Fprintf(&buf, "\treturn json.Marshal(v)\n")
+		fmt.Fprintf(&buf, "}\n")
+	}
+
+	return imports, buf.String()
+}
+
 func renderInterface(config *Config, typ schema.Type) ([]string, string) {
 	name := getNameNullable(config, typ, false)
 
@@ -365,6 +689,11 @@ func renderEnum(config *Config, typ schema.Type) string {
 }
 
 func main() {
+	var queries queryFiles
+	flag.Var(&queries, "query", "path to a .graphql file with operations to generate (may be repeated)")
+	schemaFile := flag.String("schema", "", "path to a .graphql SDL file to use instead of an introspection JSON response on stdin")
+	flag.Parse()
+
 	f, err := os.Open("config.json")
 	if err != nil {
 		log.Fatal(err)
@@ -381,15 +710,61 @@ func main() {
 			Schema schema.Schema `json:"__schema"`
 		}
 	}
-	err = json.NewDecoder(os.Stdin).Decode(&v)
-	if err != nil {
-		log.Fatal(err)
+	if *schemaFile != "" {
+		src, err := ioutil.ReadFile(*schemaFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		v.Data.Schema, err = sdl.Parse(string(src))
+		if err != nil {
+			log.Fatalf("%s: %s", *schemaFile, err)
+		}
+	} else {
+		err = json.NewDecoder(os.Stdin).Decode(&v)
+		if err != nil {
+			log.Fatal(err)
+		}
 	}
 
 	outputFiles := make(OutputFiles)
 	pkg := "fixmepkg"
 
-	for _, typ := range v.Data.Schema.Types {
+	if len(queries) > 0 {
+		doc := &query.Document{
+			Fragments: make(map[string]*query.FragmentDefinition),
+		}
+		for _, path := range queries {
+			src, err := ioutil.ReadFile(path)
+			if err != nil {
+				log.Fatal(err)
+			}
+			fileDoc, err := query.Parse(string(src))
+			if err != nil {
+				log.Fatalf("%s: %s", path, err)
+			}
+			doc.Operations = append(doc.Operations, fileDoc.Operations...)
+			for name, frag := range fileDoc.Fragments {
+				if _, ok := doc.Fragments[name]; ok {
+					log.Fatalf("%s: duplicate fragment %q", path, name)
+				}
+				doc.Fragments[name] = frag
+			}
+		}
+
+		if err := query.Validate(doc, v.Data.Schema); err != nil {
+			log.Fatal(err)
+		}
+
+		imports, chunk := renderQueryFile(&config, v.Data.Schema, doc)
+		outputFiles.Get("operations.go", pkg).Add(imports, chunk)
+	}
+
+	types := append([]schema.Type(nil), v.Data.Schema.Types...)
+	sort.Slice(types, func(i, j int) bool {
+		return typeSortName(types[i]) < typeSortName(types[j])
+	})
+
+	for _, typ := range types {
 		file := getFile(&config, typ)
 		if file == "" {
 			continue
@@ -412,37 +787,105 @@ func main() {
 		case typekind.Union:
 			chunk := renderUnion(&config, typ)
 			of.Add(nil, chunk)
+		case typekind.InputObject:
+			imports, chunk := renderInputObject(&config, typ)
+			of.Add(imports, chunk)
 		default:
 			fmt.Printf("SKIP %s %s\n", typ.Kind, *typ.Name)
 		}
 	}
 
-	for file, of := range outputFiles {
-		file = "./fixmepkg/" + file
-		err := os.MkdirAll(path.Dir(file), 0777)
-		if err != nil {
-			log.Fatal(err)
-		}
+	fileNames := make([]string, 0, len(outputFiles))
+	for file := range outputFiles {
+		fileNames = append(fileNames, file)
+	}
+	sort.Strings(fileNames)
 
-		f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
-		if err != nil {
+	for _, file := range fileNames {
+		src := renderOutputFile(file, outputFiles[file])
+
+		outPath := "./fixmepkg/" + file
+		if err := writeFile(outPath, src); err != nil {
 			log.Fatal(err)
 		}
+	}
+}
 
-		fmt.Fprintf(f, "package %s\n", of.Package)
-		if len(of.Imports) > 0 {
-			f.WriteString("\n")
-			f.WriteString("import (\n")
-			for _, im := range of.Imports {
-				fmt.Fprintf(f, "\t%q\n", im)
-			}
-			f.WriteString(")\n")
+// renderOutputFile assembles of's package clause, grouped/sorted imports,
+// and chunks into a single file and runs it through go/format.Source.
+// fileName is used only to label the diagnostic logged if formatting
+// fails; the unformatted source is returned in that case so generation
+// can still proceed.
+func renderOutputFile(fileName string, of *OutputFile) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "package %s\n", of.Package)
+
+	stdlib, thirdParty := sortImports(of.Imports)
+	if len(stdlib) > 0 || len(thirdParty) > 0 {
+		buf.WriteString("\n")
+		buf.WriteString("import (\n")
+		for _, im := range stdlib {
+			fmt.Fprintf(&buf, "\t%q\n", im)
+		}
+		if len(stdlib) > 0 && len(thirdParty) > 0 {
+			buf.WriteString("\n")
 		}
-		for _, chunk := range of.Chunks {
-			f.WriteString("\n")
-			f.WriteString(chunk)
+		for _, im := range thirdParty {
+			fmt.Fprintf(&buf, "\t%q\n", im)
+		}
+		buf.WriteString(")\n")
+	}
+	for _, chunk := range of.Chunks {
+		buf.WriteString("\n")
+		buf.WriteString(chunk)
+	}
+
+	src := buf.Bytes()
+	formatted, err := format.Source(src)
+	if err != nil {
+		log.Printf("%s: go/format: %s (writing unformatted source)", fileName, err)
+		return src
+	}
+	return formatted
+}
+
+// sortImports splits imports into a stdlib group and a third-party group,
+// each sorted alphabetically, the same grouping goimports produces.
+func sortImports(imports []string) (stdlib, thirdParty []string) {
+	for _, im := range imports {
+		if isStdlibImport(im) {
+			stdlib = append(stdlib, im)
+		} else {
+			thirdParty = append(thirdParty, im)
 		}
+	}
+	sort.Strings(stdlib)
+	sort.Strings(thirdParty)
+	return stdlib, thirdParty
+}
+
+// isStdlibImport guesses whether an import path belongs to the standard
+// library using the same heuristic as goimports: a path whose first
+// component has no dot is assumed to be stdlib.
+func isStdlibImport(importPath string) bool {
+	first := importPath
+	if i := strings.Index(importPath, "/"); i >= 0 {
+		first = importPath[:i]
+	}
+	return !strings.Contains(first, ".")
+}
 
-		f.Close()
+func writeFile(file string, src []byte) error {
+	if err := os.MkdirAll(path.Dir(file), 0777); err != nil {
+		return err
 	}
+
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(src)
+	return err
 }
@@ -0,0 +1,334 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/dmage/graphql/pkg/query"
+	"github.com/dmage/graphql/pkg/schema"
+	"github.com/dmage/graphql/pkg/schema/typekind"
+)
+
+// queryGen renders a single .graphql operation file into typed response
+// structs, a Variables struct, and a DoFoo function, mirroring the way
+// renderObject/renderInterface render the full introspection schema.
+type queryGen struct {
+	config    *Config
+	types     map[string]schema.Type
+	fragments map[string]*query.FragmentDefinition
+	chunks    []string
+
+	// usesJSON is set once a rendered selection set needed a generated
+	// UnmarshalJSON method (i.e. it has inline-fragment variants), so
+	// renderQueryFile knows whether to import "encoding/json".
+	usesJSON bool
+}
+
+func renderQueryFile(config *Config, sch schema.Schema, doc *query.Document) (imports []string, source string) {
+	types := make(map[string]schema.Type)
+	for _, typ := range sch.Types {
+		if typ.Name != nil {
+			types[*typ.Name] = typ
+		}
+	}
+
+	g := &queryGen{
+		config:    config,
+		types:     types,
+		fragments: doc.Fragments,
+	}
+
+	for _, op := range doc.Operations {
+		g.renderOperation(sch, op)
+	}
+
+	imports = []string{"context", "github.com/dmage/graphql/pkg/graphql/client"}
+	if g.usesJSON {
+		imports = append(imports, "encoding/json")
+	}
+	return imports, strings.Join(g.chunks, "\n")
+}
+
+func (g *queryGen) rootType(sch schema.Schema, op *query.OperationDefinition) schema.Type {
+	switch op.Operation {
+	case "mutation":
+		if sch.MutationType == nil {
+			log.Fatalf("operation %q: schema has no mutation type", op.Name)
+		}
+		return g.resolveType(*sch.MutationType)
+	case "subscription":
+		if sch.SubscriptionType == nil {
+			log.Fatalf("operation %q: schema has no subscription type", op.Name)
+		}
+		return g.resolveType(*sch.SubscriptionType)
+	default:
+		return g.resolveType(sch.QueryType)
+	}
+}
+
+// resolveType looks up the full definition (Fields, PossibleTypes, ...) of a
+// named type by name in the schema's top-level type list. Introspection
+// responses often embed only a name/kind reference at the point of use
+// (e.g. __schema.queryType, a field's type), so selections must be resolved
+// against the canonical entry in __schema.types instead.
+func (g *queryGen) resolveType(typ schema.Type) schema.Type {
+	if typ.Name == nil {
+		return typ
+	}
+	if full, ok := g.types[*typ.Name]; ok {
+		return full
+	}
+	return typ
+}
+
+func (g *queryGen) renderOperation(sch schema.Schema, op *query.OperationDefinition) {
+	responseType := op.Name + "Response"
+	g.renderSelectionSet(g.rootType(sch, op), responseType, op.SelectionSet)
+
+	variablesType := op.Name + "Variables"
+	g.renderVariables(variablesType, op.Variables)
+
+	g.renderDoFunc(op, responseType, variablesType)
+}
+
+func (g *queryGen) renderVariables(name string, vars []*query.VariableDefinition) {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "type %s struct {\n", name)
+	for _, v := range vars {
+		fmt.Fprintf(&buf, "\t%s %s `json:%q`\n", strings.Title(v.Name), g.goVarType(v.Type), v.Name)
+	}
+	buf.WriteString("}\n")
+	g.chunks = append(g.chunks, buf.String())
+}
+
+// goVarType maps a GraphQL operation variable type (raw syntax, e.g.
+// "[ID!]!") to the Go type used for that variable, following the same
+// nullable-means-pointer convention as getNameNullable.
+func (g *queryGen) goVarType(raw string) string {
+	nullable := true
+	s := raw
+	if strings.HasSuffix(s, "!") {
+		nullable = false
+		s = s[:len(s)-1]
+	}
+	if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		return "[]" + g.goVarType(s[1:len(s)-1])
+	}
+
+	cfg := getScalarConfig(g.config, s)
+	if cfg.Name != "" {
+		if nullable {
+			return "*" + cfg.Name
+		}
+		return cfg.Name
+	}
+
+	// Not a configured scalar: assume it's an enum or input object that is
+	// (or will be) generated under its schema name.
+	if nullable {
+		return "*" + s
+	}
+	return s
+}
+
+// renderDoFunc emits a function that executes op through a
+// *client.Client, the transport implementing retries, GraphQL error
+// surfacing, and Automatic Persisted Queries (see pkg/graphql/client).
+func (g *queryGen) renderDoFunc(op *query.OperationDefinition, responseType, variablesType string) {
+	funcName := "Do" + op.Name
+	queryText := query.Print(op, g.fragments)
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "// %s executes the %q %s operation through c and decodes its\n", funcName, op.Name, op.Operation)
+	buf.WriteString("// response into a " + responseType + ".\n")
+	fmt.Fprintf(&buf, "func %s(ctx context.Context, c *client.Client, variables %s) (*%s, error) {\n", funcName, variablesType, responseType)
+	buf.WriteString("\tvar resp " + responseType + "\n")
+	fmt.Fprintf(&buf, "\terr := c.Do(ctx, %s, variables, &resp)\n", goStringLiteral(queryText))
+	buf.WriteString("\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+	buf.WriteString("\treturn &resp, nil\n")
+	buf.WriteString("}\n")
+	g.chunks = append(g.chunks, buf.String())
+}
+
+func goStringLiteral(s string) string {
+	if !strings.Contains(s, "`") {
+		return "`" + s + "`"
+	}
+	return fmt.Sprintf("%q", s)
+}
+
+// flattenSelections expands named fragment spreads in place, leaving field
+// selections and inline fragments untouched.
+func (g *queryGen) flattenSelections(ss *query.SelectionSet) []query.Selection {
+	var out []query.Selection
+	for _, sel := range ss.Selections {
+		if spread, ok := sel.(*query.FragmentSpread); ok {
+			frag, ok := g.fragments[spread.Name]
+			if !ok {
+				log.Fatalf("unknown fragment %q", spread.Name)
+			}
+			out = append(out, g.flattenSelections(frag.SelectionSet)...)
+			continue
+		}
+		out = append(out, sel)
+	}
+	return out
+}
+
+// renderSelectionSet renders one generated struct for ss, selected against
+// typ, naming it name. It returns name for convenience when called for its
+// side effect of appending to g.chunks.
+func (g *queryGen) renderSelectionSet(typ schema.Type, name string, ss *query.SelectionSet) string {
+	fieldsByName := make(map[string]schema.Field)
+	for _, f := range typ.Fields {
+		fieldsByName[f.Name] = f
+	}
+
+	var ownFields []*query.FieldSelection
+	variantOrder := []string{}
+	variants := make(map[string]*query.SelectionSet)
+	for _, sel := range g.flattenSelections(ss) {
+		switch sel := sel.(type) {
+		case *query.FieldSelection:
+			ownFields = append(ownFields, sel)
+		case *query.InlineFragment:
+			if typ.Kind == typekind.Interface || typ.Kind == typekind.Union {
+				if _, ok := variants[sel.TypeCondition]; !ok {
+					variantOrder = append(variantOrder, sel.TypeCondition)
+				}
+				variants[sel.TypeCondition] = sel.SelectionSet
+			} else {
+				// A fragment on the concrete object type itself: its
+				// fields belong at this level.
+				for _, nested := range g.flattenSelections(sel.SelectionSet) {
+					if field, ok := nested.(*query.FieldSelection); ok {
+						ownFields = append(ownFields, field)
+					}
+				}
+			}
+		}
+	}
+
+	hasVariants := len(variantOrder) > 0
+
+	// fieldGoType renders (and names) a nested struct the first time it
+	// sees a field with its own selection set, so compute each field's Go
+	// type exactly once and reuse it below.
+	fieldTypes := make([]string, len(ownFields))
+	for i, f := range ownFields {
+		fieldTypes[i] = g.fieldGoType(typ, fieldsByName, name, f)
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "type %s struct {\n", name)
+	if hasVariants {
+		buf.WriteString("\tTypename string `json:\"__typename\"`\n\n")
+	}
+	for i, f := range ownFields {
+		fmt.Fprintf(&buf, "\tfield_%s %s `json:%q`\n", f.ResponseName(), fieldTypes[i], f.ResponseName())
+	}
+	for _, typeCondition := range variantOrder {
+		fmt.Fprintf(&buf, "\tas%s *%sAs%s\n", typeCondition, name, typeCondition)
+	}
+	buf.WriteString("}\n")
+
+	for i, f := range ownFields {
+		fmt.Fprintf(&buf, "\nfunc (o %s) %s() %s {\n", name, strings.Title(f.ResponseName()), fieldTypes[i])
+		fmt.Fprintf(&buf, "\treturn o.field_%s\n", f.ResponseName())
+		buf.WriteString("}\n")
+	}
+	for _, typeCondition := range variantOrder {
+		fmt.Fprintf(&buf, "\n// As%s returns the selection for the %q inline fragment and true\n", typeCondition, typeCondition)
+		fmt.Fprintf(&buf, "// if the underlying object had __typename %q.\n", typeCondition)
+		fmt.Fprintf(&buf, "func (o *%s) As%s() (*%sAs%s, bool) {\n", name, typeCondition, name, typeCondition)
+		fmt.Fprintf(&buf, "\treturn o.as%s, o.as%s != nil\n", typeCondition, typeCondition)
+		buf.WriteString("}\n")
+	}
+
+	if hasVariants {
+		g.usesJSON = true
+		fmt.Fprintf(&buf, "\nfunc (o *%s) UnmarshalJSON(data []byte) error {\n", name)
+		buf.WriteString("\tvar v struct {\n")
+		buf.WriteString("\t\tTypename string `json:\"__typename\"`\n")
+		for i, f := range ownFields {
+			fmt.Fprintf(&buf, "\t\tfield_%s %s `json:%q`\n", f.ResponseName(), fieldTypes[i], f.ResponseName())
+		}
+		buf.WriteString("\t}\n")
+		buf.WriteString("\terr := json.Unmarshal(data, &v)\n")
+		buf.WriteString("\tif err != nil {\n\t\treturn err\n\t}\n")
+		buf.WriteString("\to.Typename = v.Typename\n")
+		for _, f := range ownFields {
+			fmt.Fprintf(&buf, "\to.field_%s = v.field_%s\n", f.ResponseName(), f.ResponseName())
+		}
+		buf.WriteString("\tswitch v.Typename {\n")
+		for _, typeCondition := range variantOrder {
+			fmt.Fprintf(&buf, "\tcase %q:\n", typeCondition)
+			fmt.Fprintf(&buf, "\t\tvar as %sAs%s\n", name, typeCondition)
+			buf.WriteString("\t\terr = json.Unmarshal(data, &as)\n")
+			buf.WriteString("\t\tif err != nil {\n\t\t\treturn err\n\t\t}\n")
+			fmt.Fprintf(&buf, "\t\to.as%s = &as\n", typeCondition)
+		}
+		buf.WriteString("\t}\n")
+		buf.WriteString("\treturn nil\n")
+		buf.WriteString("}\n")
+	}
+
+	g.chunks = append(g.chunks, buf.String())
+
+	for _, typeCondition := range variantOrder {
+		variantType, ok := g.types[typeCondition]
+		if !ok {
+			log.Fatalf("inline fragment: unknown type %q", typeCondition)
+		}
+		g.renderSelectionSet(variantType, name+"As"+typeCondition, variants[typeCondition])
+	}
+
+	return name
+}
+
+// fieldGoType returns the Go type of a selected field, rendering (and
+// naming) a nested struct for it if it has its own selection set. typ is
+// the schema type the selection was made against; name is the generated
+// struct name being built for that selection.
+func (g *queryGen) fieldGoType(typ schema.Type, fieldsByName map[string]schema.Field, name string, sel *query.FieldSelection) string {
+	if sel.Name == "__typename" {
+		return "string"
+	}
+
+	field, ok := fieldsByName[sel.Name]
+	if !ok {
+		log.Fatalf("field %q does not exist on %q", sel.Name, name)
+	}
+
+	if sel.SelectionSet == nil {
+		return getFieldType(g.config, typ, field)
+	}
+
+	childName := name + strings.Title(sel.ResponseName())
+	goType := wrapSelectionType(field.Type, childName, true)
+	g.renderSelectionSet(g.resolveType(namedType(field.Type)), childName, sel.SelectionSet)
+	return goType
+}
+
+// wrapSelectionType mirrors getNameNullable's NonNull/List wrapping rules,
+// but substitutes leaf for the named type instead of reading typ.Name.
+func wrapSelectionType(typ schema.Type, leaf string, nullable bool) string {
+	switch typ.Kind {
+	case typekind.NonNull:
+		return wrapSelectionType(*typ.OfType, leaf, false)
+	case typekind.List:
+		return "[]" + wrapSelectionType(*typ.OfType, leaf, true)
+	}
+	if nullable {
+		return "*" + leaf
+	}
+	return leaf
+}
+
+func namedType(typ schema.Type) schema.Type {
+	for typ.Kind == typekind.NonNull || typ.Kind == typekind.List {
+		typ = *typ.OfType
+	}
+	return typ
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dmage/graphql/pkg/query"
+	"github.com/dmage/graphql/pkg/schema/sdl"
+)
+
+// TestRenderQueryFileImportsJSONForInterfaceSelections checks that a query
+// selecting into an inline fragment on an interface/union pulls in
+// "encoding/json": renderSelectionSet generates an UnmarshalJSON method for
+// such selections (to dispatch on __typename), and that method calls
+// json.Unmarshal directly, so the import can't be left out or the generated
+// operations.go fails to compile with "undefined: json".
+func TestRenderQueryFileImportsJSONForInterfaceSelections(t *testing.T) {
+	sch, err := sdl.Parse(`
+		type Query {
+			author: Actor
+		}
+
+		interface Actor {
+			login: String
+		}
+
+		type User implements Actor {
+			login: String
+			company: String
+		}
+	`)
+	if err != nil {
+		t.Fatalf("sdl.Parse: %v", err)
+	}
+
+	doc, err := query.Parse(`
+		query GetAuthor {
+			author {
+				login
+				... on User {
+					company
+				}
+			}
+		}
+	`)
+	if err != nil {
+		t.Fatalf("query.Parse: %v", err)
+	}
+
+	imports, source := renderQueryFile(&Config{}, sch, doc)
+
+	found := false
+	for _, im := range imports {
+		if im == "encoding/json" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("renderQueryFile imports = %v, want it to contain %q", imports, "encoding/json")
+	}
+	if !strings.Contains(source, "json.Unmarshal") {
+		t.Errorf("renderQueryFile did not generate a json.Unmarshal call:\n%s", source)
+	}
+}
+
+// TestRenderQueryFileOmitsJSONWithoutVariants checks the common case (no
+// interface/union selections) doesn't pick up an unused "encoding/json"
+// import, which go/format.Source's formatting pass wouldn't catch since the
+// import list, not the source text, decides what's emitted.
+func TestRenderQueryFileOmitsJSONWithoutVariants(t *testing.T) {
+	sch, err := sdl.Parse(`
+		type Query {
+			viewer: User
+		}
+
+		type User {
+			login: String
+		}
+	`)
+	if err != nil {
+		t.Fatalf("sdl.Parse: %v", err)
+	}
+
+	doc, err := query.Parse(`
+		query GetViewer {
+			viewer {
+				login
+			}
+		}
+	`)
+	if err != nil {
+		t.Fatalf("query.Parse: %v", err)
+	}
+
+	imports, _ := renderQueryFile(&Config{}, sch, doc)
+	for _, im := range imports {
+		if im == "encoding/json" {
+			t.Errorf("renderQueryFile imports = %v, want it to not contain %q", imports, "encoding/json")
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dmage/graphql/pkg/schema"
+	"github.com/dmage/graphql/pkg/schema/typekind"
+)
+
+// TestRenderOutputFileDeterministic regenerates the same OutputFile twice
+// and checks the result is byte-for-byte identical and matches a golden
+// string, covering the import grouping/sorting and go/format.Source pass.
+func TestRenderOutputFileDeterministic(t *testing.T) {
+	of := &OutputFile{
+		Package: "fixmepkg",
+		Imports: []string{"fmt", "github.com/dmage/graphql/pkg/scalars", "os"},
+		Chunks:  []string{"type Foo struct{}\n", "type Bar struct{}\n"},
+	}
+
+	want := "package fixmepkg\n" +
+		"\n" +
+		"import (\n" +
+		"\t\"fmt\"\n" +
+		"\t\"os\"\n" +
+		"\n" +
+		"\t\"github.com/dmage/graphql/pkg/scalars\"\n" +
+		")\n" +
+		"\n" +
+		"type Foo struct{}\n" +
+		"\n" +
+		"type Bar struct{}\n"
+
+	a := renderOutputFile("types.go", of)
+	b := renderOutputFile("types.go", of)
+	if !bytes.Equal(a, b) {
+		t.Fatalf("renderOutputFile is not deterministic:\n--- run 1 ---\n%s\n--- run 2 ---\n%s", a, b)
+	}
+	if string(a) != want {
+		t.Errorf("renderOutputFile() =\n%s\nwant\n%s", a, want)
+	}
+}
+
+// TestRenderInputObjectMarshalsScalarHook checks that an INPUT_OBJECT field
+// bound to a scalar with a Marshaler configured is encoded through that
+// Marshaler, not passed straight to encoding/json: a *url.URL field, for
+// instance, must not serialize as its internal struct fields.
+func TestRenderInputObjectMarshalsScalarHook(t *testing.T) {
+	name := "Issue"
+	uri := "URI"
+	str := "String"
+	homepage := "homepage"
+	title := "title"
+
+	config := &Config{
+		Scalars: map[string]ScalarConfig{
+			"URI": {
+				Type:        "*url.URL",
+				Import:      "net/url",
+				Wire:        "string",
+				Marshaler:   "github.com/dmage/graphql/pkg/scalars.MarshalURL",
+				Unmarshaler: "github.com/dmage/graphql/pkg/scalars.UnmarshalURL",
+			},
+		},
+	}
+
+	typ := schema.Type{
+		Kind: typekind.InputObject,
+		Name: &name,
+		InputFields: []schema.InputValue{
+			{Name: homepage, Type: schema.Type{Kind: typekind.Scalar, Name: &uri}},
+			{Name: title, Type: schema.Type{Kind: typekind.NonNull, OfType: &schema.Type{Kind: typekind.Scalar, Name: &str}}},
+		},
+	}
+
+	imports, chunk := renderInputObject(config, typ)
+
+	wantImports := []string{"net/url", "github.com/dmage/graphql/pkg/scalars", "encoding/json"}
+	for _, im := range wantImports {
+		found := false
+		for _, got := range imports {
+			if got == im {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("renderInputObject imports = %v, want it to contain %q", imports, im)
+		}
+	}
+
+	if !strings.Contains(chunk, "func (o Issue) MarshalJSON() ([]byte, error) {") {
+		t.Errorf("renderInputObject did not generate a MarshalJSON method:\n%s", chunk)
+	}
+	if !strings.Contains(chunk, "scalars.MarshalURL(o.Homepage)") {
+		t.Errorf("renderInputObject did not call the Marshaler for Homepage:\n%s", chunk)
+	}
+	if !strings.Contains(chunk, "v.Title = o.Title") {
+		t.Errorf("renderInputObject did not pass through the unhooked field Title:\n%s", chunk)
+	}
+}
+
+func TestSortImports(t *testing.T) {
+	stdlib, thirdParty := sortImports([]string{
+		"os",
+		"github.com/dmage/graphql/pkg/scalars",
+		"encoding/json",
+		"net/url",
+	})
+	wantStdlib := []string{"encoding/json", "net/url", "os"}
+	wantThirdParty := []string{"github.com/dmage/graphql/pkg/scalars"}
+
+	if !stringSlicesEqual(stdlib, wantStdlib) {
+		t.Errorf("sortImports stdlib = %v, want %v", stdlib, wantStdlib)
+	}
+	if !stringSlicesEqual(thirdParty, wantThirdParty) {
+		t.Errorf("sortImports thirdParty = %v, want %v", thirdParty, wantThirdParty)
+	}
+}
+
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,270 @@
+// Package client is an HTTP transport for executing GraphQL operations. It
+// surfaces the response's "errors" array as a typed Errors value, retries
+// 429/5xx responses with jittered exponential backoff honoring
+// Retry-After, supports Automatic Persisted Queries (APQ) with fallback to
+// the full query, and can send either POST-JSON or GET-with-query-string.
+// Query-driven codegen (see gen_query.go) emits operation functions that
+// call Client.Do instead of each caller re-implementing the transport.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Middleware edits an outgoing request before it's sent, e.g. to attach
+// an Authorization header or a request ID for logging.
+type Middleware func(*http.Request) error
+
+// Client executes GraphQL operations against a single endpoint.
+type Client struct {
+	// HTTPClient performs the underlying requests. http.DefaultClient is
+	// used if nil.
+	HTTPClient *http.Client
+
+	// Endpoint is the GraphQL server's URL.
+	Endpoint string
+
+	// Middlewares run, in order, on every outgoing request.
+	Middlewares []Middleware
+
+	// UseGet sends operations as GET requests with the query, variables,
+	// and APQ extensions encoded into the URL's query string, instead of
+	// a POST body. Useful for operations a CDN should be able to cache.
+	UseGet bool
+
+	// APQ enables Automatic Persisted Queries: Do first sends only the
+	// query's sha256 hash; if the server reports a PersistedQueryNotFound
+	// error, it retries once with the full query text attached.
+	APQ bool
+
+	// MaxRetries bounds how many times a request is retried after a 429
+	// or 5xx response or a network error. Zero disables retries.
+	MaxRetries int
+
+	// RetryBaseDelay is the base of the exponential backoff used between
+	// retries when the response carries no Retry-After header. Defaults
+	// to 200ms if zero.
+	RetryBaseDelay time.Duration
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+func (c *Client) retryBaseDelay() time.Duration {
+	if c.RetryBaseDelay != 0 {
+		return c.RetryBaseDelay
+	}
+	return 200 * time.Millisecond
+}
+
+// requestAttempt carries the per-call state that can change across
+// retries within a single Do (persistedOnly drops out once the server
+// reports PersistedQueryNotFound).
+type requestAttempt struct {
+	query         string
+	variables     interface{}
+	hash          string
+	persistedOnly bool
+}
+
+// Do executes query with variables and decodes the response's "data"
+// into out. If the response carries GraphQL errors, Do returns them as an
+// Errors value (out is still populated with any partial data the server
+// returned alongside them).
+func (c *Client) Do(ctx context.Context, query string, variables interface{}, out interface{}) error {
+	a := requestAttempt{
+		query:         query,
+		variables:     variables,
+		hash:          sha256Hex(query),
+		persistedOnly: c.APQ,
+	}
+
+	for {
+		data, errs, err := c.doOnce(ctx, a)
+		if err != nil {
+			return err
+		}
+		if a.persistedOnly && errs.HasCode("PersistedQueryNotFound") {
+			a.persistedOnly = false
+			continue
+		}
+		if len(data) > 0 && out != nil {
+			if jsonErr := json.Unmarshal(data, out); jsonErr != nil {
+				return jsonErr
+			}
+		}
+		if len(errs) > 0 {
+			return errs
+		}
+		return nil
+	}
+}
+
+// doOnce performs one logical request (the query/hash attempt described
+// by a), retrying on transient failures up to c.MaxRetries times.
+func (c *Client) doOnce(ctx context.Context, a requestAttempt) (json.RawMessage, Errors, error) {
+	var lastErr error
+	for attempt := 0; attempt <= c.MaxRetries; attempt++ {
+		req, err := c.newRequest(ctx, a)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, mw := range c.Middlewares {
+			if err := mw(req); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		resp, err := c.httpClient().Do(req)
+		if err != nil {
+			lastErr = err
+			if attempt == c.MaxRetries || !sleep(ctx, retryDelay(nil, attempt, c.retryBaseDelay())) {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		if shouldRetry(resp) && attempt < c.MaxRetries {
+			delay := retryDelay(resp, attempt, c.retryBaseDelay())
+			resp.Body.Close()
+			lastErr = fmt.Errorf("graphql: server returned %s", resp.Status)
+			if !sleep(ctx, delay) {
+				return nil, nil, ctx.Err()
+			}
+			continue
+		}
+
+		data, errs, err := decodeResponse(resp)
+		if err != nil {
+			return nil, nil, err
+		}
+		return data, errs, nil
+	}
+	return nil, nil, lastErr
+}
+
+func decodeResponse(resp *http.Response) (json.RawMessage, Errors, error) {
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, nil, fmt.Errorf("graphql: unexpected status %s", resp.Status)
+	}
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors Errors          `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, nil, err
+	}
+	return result.Data, result.Errors, nil
+}
+
+// sleep waits for d, or until ctx is canceled, whichever comes first. It
+// reports whether d elapsed (false means ctx was canceled).
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (c *Client) newRequest(ctx context.Context, a requestAttempt) (*http.Request, error) {
+	queryText := a.query
+	if c.APQ && a.persistedOnly {
+		queryText = ""
+	}
+
+	var ext map[string]interface{}
+	if c.APQ {
+		ext = map[string]interface{}{
+			"persistedQuery": map[string]interface{}{
+				"version":    1,
+				"sha256Hash": a.hash,
+			},
+		}
+	}
+
+	if c.UseGet {
+		return newGetRequest(ctx, c.Endpoint, queryText, a.variables, ext)
+	}
+	return newPostRequest(ctx, c.Endpoint, queryText, a.variables, ext)
+}
+
+func newPostRequest(ctx context.Context, endpoint, queryText string, variables interface{}, ext map[string]interface{}) (*http.Request, error) {
+	body := struct {
+		Query      string                 `json:"query,omitempty"`
+		Variables  interface{}            `json:"variables,omitempty"`
+		Extensions map[string]interface{} `json:"extensions,omitempty"`
+	}{
+		Query:      queryText,
+		Variables:  variables,
+		Extensions: ext,
+	}
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(buf))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+func newGetRequest(ctx context.Context, endpoint, queryText string, variables interface{}, ext map[string]interface{}) (*http.Request, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	q := u.Query()
+	if queryText != "" {
+		q.Set("query", queryText)
+	}
+	if variables != nil {
+		vbuf, err := json.Marshal(variables)
+		if err != nil {
+			return nil, err
+		}
+		if string(vbuf) != "null" {
+			q.Set("variables", string(vbuf))
+		}
+	}
+	if ext != nil {
+		ebuf, err := json.Marshal(ext)
+		if err != nil {
+			return nil, err
+		}
+		q.Set("extensions", string(ebuf))
+	}
+	u.RawQuery = q.Encode()
+
+	return http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
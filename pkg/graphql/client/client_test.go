@@ -0,0 +1,149 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDoDecodesData(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"name":"openshift"}}`))
+	}))
+	defer srv.Close()
+
+	c := &Client{Endpoint: srv.URL}
+	var out struct {
+		Name string `json:"name"`
+	}
+	if err := c.Do(context.Background(), "query { name }", nil, &out); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if out.Name != "openshift" {
+		t.Errorf("Name = %q, want %q", out.Name, "openshift")
+	}
+}
+
+func TestDoReturnsGraphQLErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":null,"errors":[{"message":"not found","path":["repo"]}]}`))
+	}))
+	defer srv.Close()
+
+	c := &Client{Endpoint: srv.URL}
+	err := c.Do(context.Background(), "query { repo { name } }", nil, &struct{}{})
+	if err == nil {
+		t.Fatal("Do: expected an error")
+	}
+	errs, ok := err.(Errors)
+	if !ok {
+		t.Fatalf("Do: error is %T, want Errors", err)
+	}
+	if len(errs) != 1 || errs[0].Message != "not found" {
+		t.Errorf("Do: errors = %+v", errs)
+	}
+}
+
+func TestDoRetriesOnServerError(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"data":{"ok":true}}`))
+	}))
+	defer srv.Close()
+
+	c := &Client{Endpoint: srv.URL, MaxRetries: 3}
+	var out struct {
+		OK bool `json:"ok"`
+	}
+	if err := c.Do(context.Background(), "query { ok }", nil, &out); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if !out.OK {
+		t.Error("OK = false, want true")
+	}
+	if requests != 3 {
+		t.Errorf("requests = %d, want 3", requests)
+	}
+}
+
+func TestDoAPQFallsBackToFullQuery(t *testing.T) {
+	var requests []struct {
+		hasQuery bool
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query      string `json:"query"`
+			Extensions struct {
+				PersistedQuery struct {
+					Sha256Hash string `json:"sha256Hash"`
+				} `json:"persistedQuery"`
+			} `json:"extensions"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, struct{ hasQuery bool }{body.Query != ""})
+
+		if body.Extensions.PersistedQuery.Sha256Hash == "" {
+			t.Errorf("request missing persistedQuery extension")
+		}
+
+		if body.Query == "" {
+			w.Write([]byte(`{"errors":[{"message":"PersistedQueryNotFound","extensions":{"code":"PersistedQueryNotFound"}}]}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"ok":true}}`))
+	}))
+	defer srv.Close()
+
+	c := &Client{Endpoint: srv.URL, APQ: true}
+	var out struct {
+		OK bool `json:"ok"`
+	}
+	if err := c.Do(context.Background(), "query { ok }", nil, &out); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if !out.OK {
+		t.Error("OK = false, want true")
+	}
+	if len(requests) != 2 {
+		t.Fatalf("requests = %d, want 2", len(requests))
+	}
+	if requests[0].hasQuery {
+		t.Error("first request should omit the query text")
+	}
+	if !requests[1].hasQuery {
+		t.Error("second request should include the full query text after PersistedQueryNotFound")
+	}
+}
+
+func TestDoUsesGet(t *testing.T) {
+	var method string
+	var rawQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		rawQuery = r.URL.RawQuery
+		w.Write([]byte(`{"data":{"ok":true}}`))
+	}))
+	defer srv.Close()
+
+	c := &Client{Endpoint: srv.URL, UseGet: true}
+	var out struct {
+		OK bool `json:"ok"`
+	}
+	if err := c.Do(context.Background(), "query { ok }", nil, &out); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if method != http.MethodGet {
+		t.Errorf("method = %q, want GET", method)
+	}
+	if rawQuery == "" {
+		t.Error("expected a non-empty query string")
+	}
+}
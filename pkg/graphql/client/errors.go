@@ -0,0 +1,57 @@
+package client
+
+import "strings"
+
+// Location is a line/column pair pointing at a place in the executed
+// query, as reported by a GraphQLError.
+//
+// http://facebook.github.io/graphql/#sec-Errors
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// GraphQLError is one entry of a response's top-level "errors" array.
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Locations  []Location             `json:"locations,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// Code returns the conventional "extensions.code" string of the error, or
+// "" if it has none. Servers implementing Automatic Persisted Queries use
+// this to signal PersistedQueryNotFound and PersistedQueryNotSupported.
+func (e GraphQLError) Code() string {
+	code, _ := e.Extensions["code"].(string)
+	return code
+}
+
+// Errors is the typed form of a response's "errors" array. It implements
+// error so a response containing GraphQL errors can be returned directly
+// from Client.Do.
+type Errors []GraphQLError
+
+func (e Errors) Error() string {
+	switch len(e) {
+	case 0:
+		return "graphql: no errors"
+	case 1:
+		return "graphql: " + e[0].Message
+	}
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Message
+	}
+	return "graphql: " + strings.Join(messages, "; ")
+}
+
+// HasCode reports whether any error in e has the given extensions.code.
+func (e Errors) HasCode(code string) bool {
+	for _, err := range e {
+		if err.Code() == code {
+			return true
+		}
+	}
+	return false
+}
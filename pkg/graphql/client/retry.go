@@ -0,0 +1,49 @@
+package client
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// shouldRetry reports whether resp's status code warrants a retry: any
+// 5xx, or 429 Too Many Requests.
+func shouldRetry(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// retryDelay computes how long to wait before retrying attempt (0-based).
+// It honors a Retry-After response header (either delay-seconds or an
+// HTTP-date) when present, and otherwise falls back to a jittered
+// exponential backoff based on base.
+func retryDelay(resp *http.Response, attempt int, base time.Duration) time.Duration {
+	if resp != nil {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return d
+		}
+	}
+
+	backoff := base << uint(attempt)
+	jitter := time.Duration(rand.Int63n(int64(backoff) + 1))
+	return backoff/2 + jitter/2
+}
+
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,86 @@
+// Package query parses and validates GraphQL operation documents (.graphql
+// files containing queries, mutations, and fragments) against a schema
+// loaded via introspection.
+package query
+
+// Document is a parsed .graphql file.
+type Document struct {
+	Operations []*OperationDefinition
+	Fragments  map[string]*FragmentDefinition
+}
+
+// OperationDefinition is a single `query Foo(...) { ... }`,
+// `mutation Foo(...) { ... }`, or `subscription Foo(...) { ... }`.
+type OperationDefinition struct {
+	Operation    string // "query", "mutation", or "subscription"
+	Name         string
+	Variables    []*VariableDefinition
+	SelectionSet *SelectionSet
+}
+
+// VariableDefinition is a `$name: Type` entry in an operation's variable
+// list. Type is the raw GraphQL type syntax (e.g. "String!", "[ID!]").
+type VariableDefinition struct {
+	Name         string
+	Type         string
+	DefaultValue string
+	HasDefault   bool
+}
+
+// SelectionSet is a `{ ... }` block.
+type SelectionSet struct {
+	Selections []Selection
+}
+
+// Selection is one of FieldSelection, InlineFragment, or FragmentSpread.
+type Selection interface{}
+
+// FieldSelection is a single selected field, optionally aliased and with
+// its own nested SelectionSet.
+type FieldSelection struct {
+	Alias        string
+	Name         string
+	Arguments    []Argument
+	SelectionSet *SelectionSet
+}
+
+// Argument is a single `name: value` entry in a field's argument list.
+// Arguments are kept in source order so that printing a parsed operation
+// reproduces the original argument order.
+type Argument struct {
+	Name  string
+	Value ArgumentValue
+}
+
+// ResponseName is the key this field occupies in the JSON response.
+func (f *FieldSelection) ResponseName() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// ArgumentValue is the raw GraphQL value syntax for a field argument
+// (e.g. a variable reference "$id" or a literal "42").
+type ArgumentValue struct {
+	Variable string // set if the argument is a variable reference
+	Literal  string // raw source text otherwise
+}
+
+// InlineFragment is a `... on TypeName { ... }` selection.
+type InlineFragment struct {
+	TypeCondition string
+	SelectionSet  *SelectionSet
+}
+
+// FragmentSpread is a `...FragmentName` selection.
+type FragmentSpread struct {
+	Name string
+}
+
+// FragmentDefinition is a `fragment Name on TypeName { ... }` declaration.
+type FragmentDefinition struct {
+	Name          string
+	TypeCondition string
+	SelectionSet  *SelectionSet
+}
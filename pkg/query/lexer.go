@@ -0,0 +1,137 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenName
+	tokenPunct
+	tokenString
+	tokenInt
+	tokenFloat
+)
+
+type token struct {
+	kind tokenKind
+	val  string
+}
+
+type lexer struct {
+	src  []rune
+	pos  int
+	toks []token
+}
+
+func lex(src string) ([]token, error) {
+	l := &lexer{src: []rune(src)}
+	for {
+		tok, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		l.toks = append(l.toks, tok)
+		if tok.kind == tokenEOF {
+			break
+		}
+	}
+	return l.toks, nil
+}
+
+func (l *lexer) peekRune() rune {
+	if l.pos >= len(l.src) {
+		return 0
+	}
+	return l.src[l.pos]
+}
+
+func (l *lexer) skipIgnored() {
+	for l.pos < len(l.src) {
+		r := l.src[l.pos]
+		switch {
+		case r == ',' || unicode.IsSpace(r):
+			l.pos++
+		case r == '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func isNameStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isNameCont(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+	if l.pos >= len(l.src) {
+		return token{kind: tokenEOF}, nil
+	}
+
+	r := l.src[l.pos]
+
+	if isNameStart(r) {
+		start := l.pos
+		for l.pos < len(l.src) && isNameCont(l.src[l.pos]) {
+			l.pos++
+		}
+		return token{kind: tokenName, val: string(l.src[start:l.pos])}, nil
+	}
+
+	if r == '"' {
+		start := l.pos
+		l.pos++
+		for l.pos < len(l.src) && l.src[l.pos] != '"' {
+			if l.src[l.pos] == '\\' {
+				l.pos++
+			}
+			l.pos++
+		}
+		if l.pos >= len(l.src) {
+			return token{}, fmt.Errorf("unterminated string literal")
+		}
+		l.pos++
+		return token{kind: tokenString, val: string(l.src[start:l.pos])}, nil
+	}
+
+	if unicode.IsDigit(r) || (r == '-' && l.pos+1 < len(l.src) && unicode.IsDigit(l.src[l.pos+1])) {
+		start := l.pos
+		l.pos++
+		isFloat := false
+		for l.pos < len(l.src) && (unicode.IsDigit(l.src[l.pos]) || l.src[l.pos] == '.' || l.src[l.pos] == 'e' || l.src[l.pos] == 'E' || l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			if l.src[l.pos] == '.' || l.src[l.pos] == 'e' || l.src[l.pos] == 'E' {
+				isFloat = true
+			}
+			l.pos++
+		}
+		kind := tokenInt
+		if isFloat {
+			kind = tokenFloat
+		}
+		return token{kind: kind, val: string(l.src[start:l.pos])}, nil
+	}
+
+	// Punctuation: $ ! ( ) : = @ [ ] { } | & and the "..." spread operator.
+	if r == '.' && l.pos+2 < len(l.src) && l.src[l.pos+1] == '.' && l.src[l.pos+2] == '.' {
+		l.pos += 3
+		return token{kind: tokenPunct, val: "..."}, nil
+	}
+	if strings.ContainsRune("$!():=@[]{}|&", r) {
+		l.pos++
+		return token{kind: tokenPunct, val: string(r)}, nil
+	}
+
+	return token{}, fmt.Errorf("unexpected character %q", r)
+}
@@ -0,0 +1,358 @@
+package query
+
+import "fmt"
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+// Parse parses the contents of a .graphql file containing operations and
+// fragments.
+func Parse(src string) (*Document, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{toks: toks}
+	doc := &Document{
+		Fragments: make(map[string]*FragmentDefinition),
+	}
+	for p.peek().kind != tokenEOF {
+		if p.peekName("fragment") {
+			frag, err := p.parseFragmentDefinition()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := doc.Fragments[frag.Name]; ok {
+				return nil, fmt.Errorf("duplicate fragment %q", frag.Name)
+			}
+			doc.Fragments[frag.Name] = frag
+			continue
+		}
+
+		op, err := p.parseOperationDefinition()
+		if err != nil {
+			return nil, err
+		}
+		doc.Operations = append(doc.Operations, op)
+	}
+	return doc, nil
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.pos]
+}
+
+func (p *parser) peekName(name string) bool {
+	t := p.peek()
+	return t.kind == tokenName && t.val == name
+}
+
+func (p *parser) peekPunct(val string) bool {
+	t := p.peek()
+	return t.kind == tokenPunct && t.val == val
+}
+
+func (p *parser) advance() token {
+	t := p.toks[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expectPunct(val string) error {
+	if !p.peekPunct(val) {
+		return fmt.Errorf("expected %q, got %q", val, p.peek().val)
+	}
+	p.advance()
+	return nil
+}
+
+func (p *parser) expectName() (string, error) {
+	t := p.peek()
+	if t.kind != tokenName {
+		return "", fmt.Errorf("expected a name, got %q", t.val)
+	}
+	p.advance()
+	return t.val, nil
+}
+
+func (p *parser) parseOperationDefinition() (*OperationDefinition, error) {
+	op := &OperationDefinition{Operation: "query"}
+
+	if p.peekName("query") || p.peekName("mutation") || p.peekName("subscription") {
+		op.Operation = p.advance().val
+		if p.peek().kind == tokenName {
+			op.Name = p.advance().val
+		}
+		if p.peekPunct("(") {
+			vars, err := p.parseVariableDefinitions()
+			if err != nil {
+				return nil, err
+			}
+			op.Variables = vars
+		}
+	}
+
+	ss, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.SelectionSet = ss
+	return op, nil
+}
+
+func (p *parser) parseVariableDefinitions() ([]*VariableDefinition, error) {
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	var vars []*VariableDefinition
+	for !p.peekPunct(")") {
+		if err := p.expectPunct("$"); err != nil {
+			return nil, err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+		typ, err := p.parseTypeRef()
+		if err != nil {
+			return nil, err
+		}
+		v := &VariableDefinition{Name: name, Type: typ}
+		if p.peekPunct("=") {
+			p.advance()
+			lit, err := p.parseValueLiteral()
+			if err != nil {
+				return nil, err
+			}
+			v.DefaultValue = lit
+			v.HasDefault = true
+		}
+		vars = append(vars, v)
+	}
+	return vars, p.expectPunct(")")
+}
+
+// parseTypeRef reads a GraphQL type reference (Name, [Type], or Type!) and
+// returns its raw source syntax, e.g. "[ID!]!".
+func (p *parser) parseTypeRef() (string, error) {
+	if p.peekPunct("[") {
+		p.advance()
+		inner, err := p.parseTypeRef()
+		if err != nil {
+			return "", err
+		}
+		if err := p.expectPunct("]"); err != nil {
+			return "", err
+		}
+		typ := "[" + inner + "]"
+		if p.peekPunct("!") {
+			p.advance()
+			typ += "!"
+		}
+		return typ, nil
+	}
+
+	name, err := p.expectName()
+	if err != nil {
+		return "", err
+	}
+	typ := name
+	if p.peekPunct("!") {
+		p.advance()
+		typ += "!"
+	}
+	return typ, nil
+}
+
+// parseValueLiteral consumes one GraphQL value and returns its raw source
+// text; nested objects/lists are not interpreted further here.
+func (p *parser) parseValueLiteral() (string, error) {
+	t := p.advance()
+	switch t.kind {
+	case tokenString, tokenInt, tokenFloat, tokenName:
+		return t.val, nil
+	case tokenPunct:
+		switch t.val {
+		case "[":
+			s := "["
+			for !p.peekPunct("]") {
+				v, err := p.parseValueLiteral()
+				if err != nil {
+					return "", err
+				}
+				s += v + " "
+			}
+			p.advance()
+			return s + "]", nil
+		case "{":
+			s := "{"
+			for !p.peekPunct("}") {
+				name, err := p.expectName()
+				if err != nil {
+					return "", err
+				}
+				if err := p.expectPunct(":"); err != nil {
+					return "", err
+				}
+				v, err := p.parseValueLiteral()
+				if err != nil {
+					return "", err
+				}
+				s += name + ": " + v + " "
+			}
+			p.advance()
+			return s + "}", nil
+		case "$":
+			name, err := p.expectName()
+			if err != nil {
+				return "", err
+			}
+			return "$" + name, nil
+		}
+	}
+	return "", fmt.Errorf("unexpected token %q in value", t.val)
+}
+
+func (p *parser) parseSelectionSet() (*SelectionSet, error) {
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+	ss := &SelectionSet{}
+	for !p.peekPunct("}") {
+		sel, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		ss.Selections = append(ss.Selections, sel)
+	}
+	return ss, p.expectPunct("}")
+}
+
+func (p *parser) parseSelection() (Selection, error) {
+	if p.peekPunct("...") {
+		p.advance()
+		if p.peekName("on") {
+			p.advance()
+			typeCondition, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			ss, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			return &InlineFragment{TypeCondition: typeCondition, SelectionSet: ss}, nil
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		return &FragmentSpread{Name: name}, nil
+	}
+
+	return p.parseFieldSelection()
+}
+
+func (p *parser) parseFieldSelection() (*FieldSelection, error) {
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	f := &FieldSelection{Name: name}
+	if p.peekPunct(":") {
+		p.advance()
+		alias := name
+		name, err = p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		f.Alias = alias
+		f.Name = name
+	}
+
+	if p.peekPunct("(") {
+		args, err := p.parseArguments()
+		if err != nil {
+			return nil, err
+		}
+		f.Arguments = args
+	}
+
+	if p.peekPunct("{") {
+		ss, err := p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+		f.SelectionSet = ss
+	}
+	return f, nil
+}
+
+func (p *parser) parseArguments() ([]Argument, error) {
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	var args []Argument
+	for !p.peekPunct(")") {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+		if p.peekPunct("$") {
+			p.advance()
+			varName, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, Argument{Name: name, Value: ArgumentValue{Variable: varName}})
+			continue
+		}
+		lit, err := p.parseValueLiteral()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, Argument{Name: name, Value: ArgumentValue{Literal: lit}})
+	}
+	return args, p.expectPunct(")")
+}
+
+func (p *parser) parseFragmentDefinition() (*FragmentDefinition, error) {
+	if err := p.expectName2("fragment"); err != nil {
+		return nil, err
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectName2("on"); err != nil {
+		return nil, err
+	}
+	typeCondition, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	ss, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	return &FragmentDefinition{Name: name, TypeCondition: typeCondition, SelectionSet: ss}, nil
+}
+
+func (p *parser) expectName2(name string) error {
+	if !p.peekName(name) {
+		return fmt.Errorf("expected %q, got %q", name, p.peek().val)
+	}
+	p.advance()
+	return nil
+}
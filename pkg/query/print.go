@@ -0,0 +1,107 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Print renders op, followed by every fragment it transitively spreads,
+// back into GraphQL source text suitable for sending over the wire.
+func Print(op *OperationDefinition, fragments map[string]*FragmentDefinition) string {
+	var buf strings.Builder
+
+	buf.WriteString(op.Operation)
+	if op.Name != "" {
+		buf.WriteString(" " + op.Name)
+	}
+	if len(op.Variables) > 0 {
+		buf.WriteString("(")
+		for i, v := range op.Variables {
+			if i != 0 {
+				buf.WriteString(", ")
+			}
+			fmt.Fprintf(&buf, "$%s: %s", v.Name, v.Type)
+			if v.HasDefault {
+				fmt.Fprintf(&buf, " = %s", v.DefaultValue)
+			}
+		}
+		buf.WriteString(")")
+	}
+	buf.WriteString(" ")
+	printSelectionSet(&buf, op.SelectionSet, 0)
+
+	used := make(map[string]bool)
+	var order []string
+	collectFragments(op.SelectionSet, fragments, used, &order)
+	for _, name := range order {
+		frag := fragments[name]
+		buf.WriteString("\n\n")
+		fmt.Fprintf(&buf, "fragment %s on %s ", frag.Name, frag.TypeCondition)
+		printSelectionSet(&buf, frag.SelectionSet, 0)
+	}
+
+	return buf.String()
+}
+
+func collectFragments(ss *SelectionSet, fragments map[string]*FragmentDefinition, used map[string]bool, order *[]string) {
+	for _, sel := range ss.Selections {
+		switch sel := sel.(type) {
+		case *FieldSelection:
+			if sel.SelectionSet != nil {
+				collectFragments(sel.SelectionSet, fragments, used, order)
+			}
+		case *InlineFragment:
+			collectFragments(sel.SelectionSet, fragments, used, order)
+		case *FragmentSpread:
+			if used[sel.Name] {
+				continue
+			}
+			used[sel.Name] = true
+			if frag, ok := fragments[sel.Name]; ok {
+				collectFragments(frag.SelectionSet, fragments, used, order)
+			}
+			*order = append(*order, sel.Name)
+		}
+	}
+}
+
+func printSelectionSet(buf *strings.Builder, ss *SelectionSet, indent int) {
+	pad := strings.Repeat("\t", indent)
+	buf.WriteString("{\n")
+	for _, sel := range ss.Selections {
+		buf.WriteString(pad + "\t")
+		switch sel := sel.(type) {
+		case *FieldSelection:
+			if sel.Alias != "" {
+				fmt.Fprintf(buf, "%s: ", sel.Alias)
+			}
+			buf.WriteString(sel.Name)
+			if len(sel.Arguments) > 0 {
+				buf.WriteString("(")
+				for i, arg := range sel.Arguments {
+					if i != 0 {
+						buf.WriteString(", ")
+					}
+					if arg.Value.Variable != "" {
+						fmt.Fprintf(buf, "%s: $%s", arg.Name, arg.Value.Variable)
+					} else {
+						fmt.Fprintf(buf, "%s: %s", arg.Name, arg.Value.Literal)
+					}
+				}
+				buf.WriteString(")")
+			}
+			if sel.SelectionSet != nil {
+				buf.WriteString(" ")
+				printSelectionSet(buf, sel.SelectionSet, indent+1)
+			} else {
+				buf.WriteString("\n")
+			}
+		case *InlineFragment:
+			fmt.Fprintf(buf, "... on %s ", sel.TypeCondition)
+			printSelectionSet(buf, sel.SelectionSet, indent+1)
+		case *FragmentSpread:
+			fmt.Fprintf(buf, "...%s\n", sel.Name)
+		}
+	}
+	buf.WriteString(pad + "}\n")
+}
@@ -0,0 +1,38 @@
+package query
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestPrintArgumentOrderIsStable checks that a field's arguments are printed
+// in the order they appeared in the source, and that printing the same
+// parsed operation repeatedly always produces identical text. Arguments
+// used to be stored in a map, so the rendered order (and therefore the
+// generated Go source for a query with multiple arguments on a field)
+// varied from run to run on an unchanged input.
+func TestPrintArgumentOrderIsStable(t *testing.T) {
+	doc, err := Parse(`
+		query GetUser($id: ID!) {
+			user(id: $id, name: "bob", active: true, limit: 10) {
+				id
+			}
+		}
+	`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	want := Print(doc.Operations[0], doc.Fragments)
+	for i := 0; i < 20; i++ {
+		got := Print(doc.Operations[0], doc.Fragments)
+		if got != want {
+			t.Fatalf("Print is not deterministic on run %d:\n--- first ---\n%s\n--- run %d ---\n%s", i, want, i, got)
+		}
+	}
+
+	const wantArgs = "user(id: $id, name: \"bob\", active: true, limit: 10) {"
+	if !strings.Contains(want, wantArgs) {
+		t.Errorf("Print() = %q, want it to contain %q (arguments in source order)", want, wantArgs)
+	}
+}
@@ -0,0 +1,143 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/dmage/graphql/pkg/schema"
+	"github.com/dmage/graphql/pkg/schema/typekind"
+)
+
+// namedType strips NON_NULL/LIST wrappers and returns the underlying named
+// type (object, interface, union, enum, scalar, or input object).
+func namedType(typ schema.Type) schema.Type {
+	for typ.Kind == typekind.NonNull || typ.Kind == typekind.List {
+		typ = *typ.OfType
+	}
+	return typ
+}
+
+// resolveType looks up the full definition (Fields, PossibleTypes, ...) of
+// a named type in the schema's top-level type list. Introspection
+// responses often embed only a name/kind reference at the point of use
+// (e.g. __schema.queryType, a field's type), so selections must be
+// resolved against the canonical entry in __schema.types instead.
+func resolveType(types map[string]schema.Type, typ schema.Type) schema.Type {
+	if typ.Name == nil {
+		return typ
+	}
+	if full, ok := types[*typ.Name]; ok {
+		return full
+	}
+	return typ
+}
+
+// Validate checks that every operation and fragment in doc selects fields
+// that exist on sch, that inline fragments name a type that exists and
+// fragment spreads name a declared fragment, and that field arguments only
+// reference declared variables. Whether a type condition can apply to the
+// enclosing selection is not checked, and a variable referenced inside a
+// fragment definition is rejected.
+func Validate(doc *Document, sch schema.Schema) error {
+	types := make(map[string]schema.Type)
+	for _, typ := range sch.Types {
+		if typ.Name != nil {
+			types[*typ.Name] = typ
+		}
+	}
+
+	for _, frag := range doc.Fragments {
+		typ, ok := types[frag.TypeCondition]
+		if !ok {
+			return fmt.Errorf("fragment %q: unknown type %q", frag.Name, frag.TypeCondition)
+		}
+		if err := validateSelectionSet(types, doc.Fragments, nil, typ, frag.SelectionSet); err != nil {
+			return fmt.Errorf("fragment %q: %w", frag.Name, err)
+		}
+	}
+
+	for _, op := range doc.Operations {
+		var root *schema.Type
+		switch op.Operation {
+		case "query":
+			root = &sch.QueryType
+		case "mutation":
+			root = sch.MutationType
+		case "subscription":
+			root = sch.SubscriptionType
+		default:
+			return fmt.Errorf("operation %q: unknown operation type %q", op.Name, op.Operation)
+		}
+		if root == nil {
+			return fmt.Errorf("operation %q: schema does not define a %s type", op.Name, op.Operation)
+		}
+
+		vars := make(map[string]*VariableDefinition)
+		for _, v := range op.Variables {
+			vars[v.Name] = v
+		}
+		if err := validateSelectionSet(types, doc.Fragments, vars, resolveType(types, *root), op.SelectionSet); err != nil {
+			name := op.Name
+			if name == "" {
+				name = "<anonymous>"
+			}
+			return fmt.Errorf("operation %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func validateSelectionSet(types map[string]schema.Type, fragments map[string]*FragmentDefinition, vars map[string]*VariableDefinition, typ schema.Type, ss *SelectionSet) error {
+	fields := make(map[string]schema.Field)
+	for _, f := range typ.Fields {
+		fields[f.Name] = f
+	}
+
+	for _, sel := range ss.Selections {
+		switch sel := sel.(type) {
+		case *FieldSelection:
+			if sel.Name == "__typename" {
+				continue
+			}
+			field, ok := fields[sel.Name]
+			if !ok {
+				return fmt.Errorf("field %q does not exist on type %q", sel.Name, *typ.Name)
+			}
+			for _, arg := range sel.Arguments {
+				if arg.Value.Variable == "" {
+					continue
+				}
+				if vars == nil {
+					return fmt.Errorf("field %q: argument %q references $%s outside of an operation", sel.Name, arg.Name, arg.Value.Variable)
+				}
+				if _, ok := vars[arg.Value.Variable]; !ok {
+					return fmt.Errorf("field %q: argument %q references undeclared variable $%s", sel.Name, arg.Name, arg.Value.Variable)
+				}
+			}
+			if sel.SelectionSet != nil {
+				fieldType := resolveType(types, namedType(field.Type))
+				if err := validateSelectionSet(types, fragments, vars, fieldType, sel.SelectionSet); err != nil {
+					return fmt.Errorf("field %q: %w", sel.Name, err)
+				}
+			}
+		case *InlineFragment:
+			condType, ok := types[sel.TypeCondition]
+			if !ok {
+				return fmt.Errorf("inline fragment: unknown type %q", sel.TypeCondition)
+			}
+			if err := validateSelectionSet(types, fragments, vars, condType, sel.SelectionSet); err != nil {
+				return fmt.Errorf("inline fragment on %q: %w", sel.TypeCondition, err)
+			}
+		case *FragmentSpread:
+			if _, ok := fragments[sel.Name]; !ok {
+				return fmt.Errorf("spread of undeclared fragment %q", sel.Name)
+			}
+			// The fragment's own selection set is validated once, against
+			// its declared type condition, in Validate.
+		default:
+			return fmt.Errorf("unknown selection %T", sel)
+		}
+	}
+
+	return nil
+}
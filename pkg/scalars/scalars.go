@@ -0,0 +1,67 @@
+// Package scalars provides Marshaler/Unmarshaler bindings for common
+// custom GraphQL scalars, for use with gen.go's ScalarConfig.Marshaler and
+// ScalarConfig.Unmarshaler.
+package scalars
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// UnmarshalTime parses an RFC 3339 timestamp, the wire representation used
+// by scalars such as DateTime and GitTimestamp.
+func UnmarshalTime(s string) (time.Time, error) {
+	return time.Parse(time.RFC3339, s)
+}
+
+// MarshalTime formats t as an RFC 3339 timestamp.
+func MarshalTime(t time.Time) (string, error) {
+	return t.Format(time.RFC3339), nil
+}
+
+// UnmarshalURL parses the wire representation used by scalars such as URI.
+func UnmarshalURL(s string) (*url.URL, error) {
+	return url.Parse(s)
+}
+
+// MarshalURL serializes u back to its wire representation.
+func MarshalURL(u *url.URL) (string, error) {
+	if u == nil {
+		return "", nil
+	}
+	return u.String(), nil
+}
+
+// UnmarshalBigInt parses a base-10 integer, the wire representation used
+// by scalars such as BigInt.
+func UnmarshalBigInt(s string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("scalars: invalid BigInt %q", s)
+	}
+	return n, nil
+}
+
+// MarshalBigInt serializes n back to its wire representation.
+func MarshalBigInt(n *big.Int) (string, error) {
+	if n == nil {
+		return "", nil
+	}
+	return n.String(), nil
+}
+
+// UnmarshalBytes decodes a hex string, the wire representation used by
+// scalars such as Bytes and GitObjectID. A leading "0x" is accepted and
+// stripped, matching Ethereum's convention for Bytes/Bytes32.
+func UnmarshalBytes(s string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
+
+// MarshalBytes serializes b back to a hex string.
+func MarshalBytes(b []byte) (string, error) {
+	return hex.EncodeToString(b), nil
+}
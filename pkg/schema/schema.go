@@ -24,9 +24,29 @@ type Type struct {
 	Fields        []Field
 	Interfaces    []Type
 	PossibleTypes []Type
-	//EnumValues []EnumValue
-	//InputFields []InputField
-	OfType *Type
+	EnumValues    []EnumValue
+	InputFields   []InputValue
+	OfType        *Type
+}
+
+// EnumValue is one member of an ENUM type.
+type EnumValue struct {
+	Name              string
+	Description       *string
+	IsDeprecated      bool
+	DeprecationReason *string
+}
+
+// InputValue is one field of an INPUT_OBJECT type, or one argument of a
+// Field. DefaultValue, when not nil, is the default's GraphQL literal
+// text, not a coerced Go value: as introspection reports it (e.g. "10",
+// "FOO", "[1, 2]"), or, in a schema built by package sdl, as that parser
+// re-spells it (e.g. "[1 2 ]").
+type InputValue struct {
+	Name         string
+	Description  *string
+	Type         Type
+	DefaultValue *string
 }
 
 func (t Type) goName(nullable bool) string {
@@ -81,32 +101,14 @@ func (t Type) GoName() string {
 }
 
 type Field struct {
-	Name        string
-	Description *string
-	// Args []InputValue
+	Name              string
+	Description       *string
+	Args              []InputValue
 	Type              Type
 	IsDeprecated      bool
 	DeprecationReason *string
 }
 
-/*
-type __InputValue {
-  name: String!
-  description: String
-  type: __Type!
-  defaultValue: String
-}
-*/
-
-/*
-type __EnumValue {
-  name: String!
-  description: String
-  isDeprecated: Boolean!
-  deprecationReason: String
-}
-*/
-
 /*
 type __Directive {
   name: String!
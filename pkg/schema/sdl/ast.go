@@ -0,0 +1,39 @@
+package sdl
+
+// fieldDef is a field of an object or interface type definition, an
+// argument of such a field, or the value of an input object field. args is
+// only populated for object/interface fields.
+type fieldDef struct {
+	name        string
+	description *string
+	args        []fieldDef
+	typeRef     string
+	defaultVal  string
+	hasDefault  bool
+}
+
+type enumValueDef struct {
+	name        string
+	description *string
+}
+
+type typeDef struct {
+	kind        string // "type", "interface", "union", "enum", "input", "scalar"
+	name        string
+	description *string
+	implements  []string
+	fields      []fieldDef
+	members     []string // union members
+	values      []enumValueDef
+}
+
+type schemaDef struct {
+	query        string
+	mutation     string
+	subscription string
+}
+
+type document struct {
+	schema *schemaDef
+	types  []*typeDef
+}
@@ -0,0 +1,210 @@
+package sdl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dmage/graphql/pkg/schema"
+	"github.com/dmage/graphql/pkg/schema/typekind"
+)
+
+var builtinScalars = []string{"String", "Int", "Float", "Boolean", "ID"}
+
+// Parse parses an SDL document into a schema.Schema, resolving `implements`
+// chains and `union X = A | B` declarations into PossibleTypes the same
+// way the introspection system does.
+func Parse(src string) (schema.Schema, error) {
+	doc, err := parseDocument(src)
+	if err != nil {
+		return schema.Schema{}, err
+	}
+
+	kinds := make(map[string]typekind.TypeKind)
+	defs := make(map[string]*typeDef)
+	var order []string
+	for _, name := range builtinScalars {
+		kinds[name] = typekind.Scalar
+	}
+	for _, td := range doc.types {
+		if _, ok := defs[td.name]; ok {
+			return schema.Schema{}, fmt.Errorf("duplicate type definition %q", td.name)
+		}
+		defs[td.name] = td
+		order = append(order, td.name)
+		kinds[td.name] = typeDefKind(td.kind)
+	}
+
+	types := make(map[string]*schema.Type)
+	for name, kind := range kinds {
+		types[name] = &schema.Type{Kind: kind, Name: stringPtr(name)}
+	}
+
+	for _, name := range order {
+		td := defs[name]
+		typ := types[name]
+		typ.Description = td.description
+
+		switch td.kind {
+		case "type", "interface":
+			fields, err := buildFields(types, td.fields)
+			if err != nil {
+				return schema.Schema{}, fmt.Errorf("type %q: %w", name, err)
+			}
+			typ.Fields = fields
+			for _, iname := range td.implements {
+				iface, ok := types[iname]
+				if !ok {
+					return schema.Schema{}, fmt.Errorf("type %q: unknown interface %q", name, iname)
+				}
+				typ.Interfaces = append(typ.Interfaces, shallow(*iface))
+				iface.PossibleTypes = append(iface.PossibleTypes, shallow(*typ))
+			}
+		case "union":
+			for _, member := range td.members {
+				mtyp, ok := types[member]
+				if !ok {
+					return schema.Schema{}, fmt.Errorf("union %q: unknown member %q", name, member)
+				}
+				typ.PossibleTypes = append(typ.PossibleTypes, shallow(*mtyp))
+			}
+		case "enum":
+			for _, v := range td.values {
+				typ.EnumValues = append(typ.EnumValues, schema.EnumValue{
+					Name:        v.name,
+					Description: v.description,
+				})
+			}
+		case "input":
+			fields, err := buildInputFields(types, td.fields)
+			if err != nil {
+				return schema.Schema{}, fmt.Errorf("input %q: %w", name, err)
+			}
+			typ.InputFields = fields
+		case "scalar":
+			// Nothing further to resolve.
+		}
+	}
+
+	var sch schema.Schema
+	queryName, mutationName, subscriptionName := "Query", "Mutation", "Subscription"
+	if doc.schema != nil {
+		queryName, mutationName, subscriptionName = doc.schema.query, doc.schema.mutation, doc.schema.subscription
+	}
+
+	queryType, ok := types[queryName]
+	if !ok {
+		return schema.Schema{}, fmt.Errorf("schema has no query type %q", queryName)
+	}
+	sch.QueryType = *queryType
+
+	if mutationType, ok := types[mutationName]; ok {
+		sch.MutationType = mutationType
+	}
+	if subscriptionType, ok := types[subscriptionName]; ok {
+		sch.SubscriptionType = subscriptionType
+	}
+
+	sch.Types = make([]schema.Type, 0, len(order))
+	for _, name := range order {
+		sch.Types = append(sch.Types, *types[name])
+	}
+
+	return sch, nil
+}
+
+func typeDefKind(kind string) typekind.TypeKind {
+	switch kind {
+	case "type":
+		return typekind.Object
+	case "interface":
+		return typekind.Interface
+	case "union":
+		return typekind.Union
+	case "enum":
+		return typekind.Enum
+	case "input":
+		return typekind.InputObject
+	case "scalar":
+		return typekind.Scalar
+	}
+	panic("unknown type definition kind " + kind)
+}
+
+func buildFields(types map[string]*schema.Type, defs []fieldDef) ([]schema.Field, error) {
+	fields := make([]schema.Field, 0, len(defs))
+	for _, fd := range defs {
+		typ, err := resolveTypeRef(types, fd.typeRef)
+		if err != nil {
+			return nil, err
+		}
+		args, err := buildInputFields(types, fd.args)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", fd.name, err)
+		}
+		fields = append(fields, schema.Field{
+			Name:        fd.name,
+			Description: fd.description,
+			Args:        args,
+			Type:        typ,
+		})
+	}
+	return fields, nil
+}
+
+func buildInputFields(types map[string]*schema.Type, defs []fieldDef) ([]schema.InputValue, error) {
+	fields := make([]schema.InputValue, 0, len(defs))
+	for _, fd := range defs {
+		typ, err := resolveTypeRef(types, fd.typeRef)
+		if err != nil {
+			return nil, err
+		}
+		iv := schema.InputValue{
+			Name:        fd.name,
+			Description: fd.description,
+			Type:        typ,
+		}
+		if fd.hasDefault {
+			iv.DefaultValue = stringPtr(fd.defaultVal)
+		}
+		fields = append(fields, iv)
+	}
+	return fields, nil
+}
+
+// resolveTypeRef converts a raw type reference ("[ID!]!") into a
+// schema.Type with NON_NULL/LIST wrapping around a shallow reference to
+// the named type, the same shape an introspection response uses at the
+// point a field is declared.
+func resolveTypeRef(types map[string]*schema.Type, ref string) (schema.Type, error) {
+	if strings.HasSuffix(ref, "!") {
+		inner, err := resolveTypeRef(types, ref[:len(ref)-1])
+		if err != nil {
+			return schema.Type{}, err
+		}
+		return schema.Type{Kind: typekind.NonNull, OfType: &inner}, nil
+	}
+	if strings.HasPrefix(ref, "[") && strings.HasSuffix(ref, "]") {
+		inner, err := resolveTypeRef(types, ref[1:len(ref)-1])
+		if err != nil {
+			return schema.Type{}, err
+		}
+		return schema.Type{Kind: typekind.List, OfType: &inner}, nil
+	}
+
+	typ, ok := types[ref]
+	if !ok {
+		return schema.Type{}, fmt.Errorf("unknown type %q", ref)
+	}
+	return shallow(*typ), nil
+}
+
+// shallow returns a name/kind reference to typ, the same shape
+// introspection responses use when referring to a type from elsewhere
+// (the full definition lives once in __schema.types).
+func shallow(typ schema.Type) schema.Type {
+	return schema.Type{Kind: typ.Kind, Name: typ.Name}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,87 @@
+package sdl
+
+import (
+	"testing"
+
+	"github.com/dmage/graphql/pkg/schema"
+)
+
+// TestParseDefaultValues checks that default values on INPUT_OBJECT fields
+// are kept as GraphQL literal text, for each of the value kinds the spec
+// allows a default to take: scalar, enum, list, and nested input object.
+// List and object literals come back re-spelled by the parser: commas are
+// dropped and each item or field is followed by a single space.
+func TestParseDefaultValues(t *testing.T) {
+	sch, err := Parse(`
+		type Query {
+			issue(id: ID!): Int
+		}
+
+		input Point {
+			x: Int
+			y: Int
+		}
+
+		enum Priority {
+			LOW
+			HIGH
+		}
+
+		input Issue {
+			title: String = "untitled"
+			priority: Priority = LOW
+			labelIds: [ID!] = []
+			tags: [String!] = ["bug", "triage"]
+			origin: Point = {x: 0, y: 0}
+			assignee: String
+		}
+	`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	fields := inputFields(t, sch, "Issue")
+
+	wantDefaults := map[string]string{
+		"title":    `"untitled"`,
+		"priority": "LOW",
+		"labelIds": "[]",
+		"tags":     `["bug" "triage" ]`,
+		"origin":   "{x: 0 y: 0 }",
+	}
+	for name, want := range wantDefaults {
+		f, ok := fields[name]
+		if !ok {
+			t.Fatalf("field %q not found", name)
+		}
+		if f.DefaultValue == nil {
+			t.Fatalf("field %q: expected a default value, got nil", name)
+		}
+		if *f.DefaultValue != want {
+			t.Errorf("field %q: DefaultValue = %q, want %q", name, *f.DefaultValue, want)
+		}
+	}
+
+	f, ok := fields["assignee"]
+	if !ok {
+		t.Fatalf("field %q not found", "assignee")
+	}
+	if f.DefaultValue != nil {
+		t.Errorf("field %q: DefaultValue = %q, want nil", "assignee", *f.DefaultValue)
+	}
+}
+
+func inputFields(t *testing.T, sch schema.Schema, name string) map[string]schema.InputValue {
+	t.Helper()
+	for _, typ := range sch.Types {
+		if typ.Name != nil && *typ.Name == name {
+			fields := make(map[string]schema.InputValue, len(typ.InputFields))
+			for _, f := range typ.InputFields {
+				fields[f.Name] = f
+			}
+			return fields
+		}
+	}
+	t.Fatalf("type %q not found", name)
+	return nil
+}
@@ -0,0 +1,529 @@
+package sdl
+
+import (
+	"fmt"
+	"strings"
+)
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func parseDocument(src string) (*document, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{toks: toks}
+	doc := &document{}
+	for p.peek().kind != tokenEOF {
+		var description *string
+		if p.peek().kind == tokenString || p.peek().kind == tokenBlockString {
+			d := unquote(p.advance().val)
+			description = &d
+		}
+
+		switch {
+		case p.peekName("schema"):
+			sd, err := p.parseSchemaDef()
+			if err != nil {
+				return nil, err
+			}
+			doc.schema = sd
+		case p.peekName("type"):
+			td, err := p.parseTypeDef("type")
+			if err != nil {
+				return nil, err
+			}
+			td.description = description
+			doc.types = append(doc.types, td)
+		case p.peekName("interface"):
+			td, err := p.parseTypeDef("interface")
+			if err != nil {
+				return nil, err
+			}
+			td.description = description
+			doc.types = append(doc.types, td)
+		case p.peekName("input"):
+			td, err := p.parseTypeDef("input")
+			if err != nil {
+				return nil, err
+			}
+			td.description = description
+			doc.types = append(doc.types, td)
+		case p.peekName("union"):
+			td, err := p.parseUnionDef()
+			if err != nil {
+				return nil, err
+			}
+			td.description = description
+			doc.types = append(doc.types, td)
+		case p.peekName("enum"):
+			td, err := p.parseEnumDef()
+			if err != nil {
+				return nil, err
+			}
+			td.description = description
+			doc.types = append(doc.types, td)
+		case p.peekName("scalar"):
+			td, err := p.parseScalarDef()
+			if err != nil {
+				return nil, err
+			}
+			td.description = description
+			doc.types = append(doc.types, td)
+		case p.peekName("directive"):
+			if err := p.skipDirectiveDef(); err != nil {
+				return nil, err
+			}
+		case p.peekName("extend"):
+			return nil, fmt.Errorf("type extensions are not supported")
+		default:
+			return nil, fmt.Errorf("unexpected token %q at top level", p.peek().val)
+		}
+	}
+	return doc, nil
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.pos]
+}
+
+func (p *parser) peekName(name string) bool {
+	t := p.peek()
+	return t.kind == tokenName && t.val == name
+}
+
+func (p *parser) peekPunct(val string) bool {
+	t := p.peek()
+	return t.kind == tokenPunct && t.val == val
+}
+
+func (p *parser) advance() token {
+	t := p.toks[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expectName(name string) error {
+	if !p.peekName(name) {
+		return fmt.Errorf("expected %q, got %q", name, p.peek().val)
+	}
+	p.advance()
+	return nil
+}
+
+func (p *parser) expectPunct(val string) error {
+	if !p.peekPunct(val) {
+		return fmt.Errorf("expected %q, got %q", val, p.peek().val)
+	}
+	p.advance()
+	return nil
+}
+
+func (p *parser) readName() (string, error) {
+	t := p.peek()
+	if t.kind != tokenName {
+		return "", fmt.Errorf("expected a name, got %q", t.val)
+	}
+	p.advance()
+	return t.val, nil
+}
+
+func (p *parser) parseSchemaDef() (*schemaDef, error) {
+	if err := p.expectName("schema"); err != nil {
+		return nil, err
+	}
+	p.skipDirectives()
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+	sd := &schemaDef{}
+	for !p.peekPunct("}") {
+		op, err := p.readName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+		name, err := p.readName()
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case "query":
+			sd.query = name
+		case "mutation":
+			sd.mutation = name
+		case "subscription":
+			sd.subscription = name
+		default:
+			return nil, fmt.Errorf("unknown root operation type %q", op)
+		}
+	}
+	return sd, p.expectPunct("}")
+}
+
+func (p *parser) parseTypeDef(kind string) (*typeDef, error) {
+	if err := p.expectName(kind); err != nil {
+		return nil, err
+	}
+	name, err := p.readName()
+	if err != nil {
+		return nil, err
+	}
+	td := &typeDef{kind: kind, name: name}
+
+	if p.peekName("implements") {
+		p.advance()
+		for {
+			// Accept an optional leading/joining "&" (the leading one is
+			// non-standard but harmless to allow).
+			if p.peekPunct("&") {
+				p.advance()
+			}
+			iname, err := p.readName()
+			if err != nil {
+				return nil, err
+			}
+			td.implements = append(td.implements, iname)
+			if !p.peekPunct("&") {
+				break
+			}
+		}
+	}
+
+	p.skipDirectives()
+
+	if p.peekPunct("{") {
+		p.advance()
+		for !p.peekPunct("}") {
+			f, err := p.parseFieldDef()
+			if err != nil {
+				return nil, err
+			}
+			td.fields = append(td.fields, f)
+		}
+		if err := p.expectPunct("}"); err != nil {
+			return nil, err
+		}
+	}
+
+	return td, nil
+}
+
+func (p *parser) parseFieldDef() (fieldDef, error) {
+	var f fieldDef
+	if p.peek().kind == tokenString || p.peek().kind == tokenBlockString {
+		d := unquote(p.advance().val)
+		f.description = &d
+	}
+	name, err := p.readName()
+	if err != nil {
+		return f, err
+	}
+	f.name = name
+
+	if p.peekPunct("(") {
+		p.advance()
+		for !p.peekPunct(")") {
+			arg, err := p.parseFieldDef()
+			if err != nil {
+				return f, err
+			}
+			f.args = append(f.args, arg)
+		}
+		if err := p.expectPunct(")"); err != nil {
+			return f, err
+		}
+	}
+
+	if err := p.expectPunct(":"); err != nil {
+		return f, err
+	}
+	typeRef, err := p.parseTypeRef()
+	if err != nil {
+		return f, err
+	}
+	f.typeRef = typeRef
+
+	if p.peekPunct("=") {
+		p.advance()
+		lit, err := p.parseValueLiteral()
+		if err != nil {
+			return f, err
+		}
+		f.defaultVal = lit
+		f.hasDefault = true
+	}
+
+	p.skipDirectives()
+
+	return f, nil
+}
+
+func (p *parser) parseTypeRef() (string, error) {
+	if p.peekPunct("[") {
+		p.advance()
+		inner, err := p.parseTypeRef()
+		if err != nil {
+			return "", err
+		}
+		if err := p.expectPunct("]"); err != nil {
+			return "", err
+		}
+		typ := "[" + inner + "]"
+		if p.peekPunct("!") {
+			p.advance()
+			typ += "!"
+		}
+		return typ, nil
+	}
+
+	name, err := p.readName()
+	if err != nil {
+		return "", err
+	}
+	typ := name
+	if p.peekPunct("!") {
+		p.advance()
+		typ += "!"
+	}
+	return typ, nil
+}
+
+func (p *parser) parseValueLiteral() (string, error) {
+	t := p.advance()
+	switch t.kind {
+	case tokenString, tokenBlockString, tokenInt, tokenFloat, tokenName:
+		return t.val, nil
+	case tokenPunct:
+		switch t.val {
+		case "[":
+			s := "["
+			for !p.peekPunct("]") {
+				v, err := p.parseValueLiteral()
+				if err != nil {
+					return "", err
+				}
+				s += v + " "
+			}
+			p.advance()
+			return s + "]", nil
+		case "{":
+			s := "{"
+			for !p.peekPunct("}") {
+				name, err := p.readName()
+				if err != nil {
+					return "", err
+				}
+				if err := p.expectPunct(":"); err != nil {
+					return "", err
+				}
+				v, err := p.parseValueLiteral()
+				if err != nil {
+					return "", err
+				}
+				s += name + ": " + v + " "
+			}
+			p.advance()
+			return s + "}", nil
+		case "$":
+			name, err := p.readName()
+			if err != nil {
+				return "", err
+			}
+			return "$" + name, nil
+		}
+	}
+	return "", fmt.Errorf("unexpected token %q in value", t.val)
+}
+
+func (p *parser) parseUnionDef() (*typeDef, error) {
+	if err := p.expectName("union"); err != nil {
+		return nil, err
+	}
+	name, err := p.readName()
+	if err != nil {
+		return nil, err
+	}
+	td := &typeDef{kind: "union", name: name}
+
+	p.skipDirectives()
+
+	if err := p.expectPunct("="); err != nil {
+		return nil, err
+	}
+	for {
+		if p.peekPunct("|") {
+			p.advance()
+		}
+		member, err := p.readName()
+		if err != nil {
+			return nil, err
+		}
+		td.members = append(td.members, member)
+		if !p.peekPunct("|") {
+			break
+		}
+	}
+	return td, nil
+}
+
+func (p *parser) parseEnumDef() (*typeDef, error) {
+	if err := p.expectName("enum"); err != nil {
+		return nil, err
+	}
+	name, err := p.readName()
+	if err != nil {
+		return nil, err
+	}
+	td := &typeDef{kind: "enum", name: name}
+
+	p.skipDirectives()
+
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+	for !p.peekPunct("}") {
+		var description *string
+		if p.peek().kind == tokenString || p.peek().kind == tokenBlockString {
+			d := unquote(p.advance().val)
+			description = &d
+		}
+		val, err := p.readName()
+		if err != nil {
+			return nil, err
+		}
+		p.skipDirectives()
+		td.values = append(td.values, enumValueDef{name: val, description: description})
+	}
+	return td, p.expectPunct("}")
+}
+
+func (p *parser) parseScalarDef() (*typeDef, error) {
+	if err := p.expectName("scalar"); err != nil {
+		return nil, err
+	}
+	name, err := p.readName()
+	if err != nil {
+		return nil, err
+	}
+	p.skipDirectives()
+	return &typeDef{kind: "scalar", name: name}, nil
+}
+
+// skipDirectiveDef consumes a `directive @name(...) on LOCATION | LOCATION`
+// definition without recording it: schema.Schema has no place to put
+// directives yet.
+func (p *parser) skipDirectiveDef() error {
+	if err := p.expectName("directive"); err != nil {
+		return err
+	}
+	if err := p.expectPunct("@"); err != nil {
+		return err
+	}
+	if _, err := p.readName(); err != nil {
+		return err
+	}
+	if p.peekPunct("(") {
+		p.advance()
+		depth := 1
+		for depth > 0 {
+			t := p.advance()
+			if t.kind == tokenEOF {
+				return fmt.Errorf("unterminated directive argument list")
+			}
+			if t.kind == tokenPunct && t.val == "(" {
+				depth++
+			}
+			if t.kind == tokenPunct && t.val == ")" {
+				depth--
+			}
+		}
+	}
+	if !p.peekName("on") {
+		return fmt.Errorf("expected \"on\" in directive definition")
+	}
+	p.advance()
+	for {
+		if p.peekPunct("|") {
+			p.advance()
+		}
+		if _, err := p.readName(); err != nil {
+			return err
+		}
+		if !p.peekPunct("|") {
+			break
+		}
+	}
+	return nil
+}
+
+// skipDirectives consumes zero or more `@name(...)` directive applications.
+func (p *parser) skipDirectives() {
+	for p.peekPunct("@") {
+		p.advance()
+		p.readName()
+		if p.peekPunct("(") {
+			p.advance()
+			depth := 1
+			for depth > 0 {
+				t := p.advance()
+				if t.kind == tokenEOF {
+					return
+				}
+				if t.kind == tokenPunct && t.val == "(" {
+					depth++
+				}
+				if t.kind == tokenPunct && t.val == ")" {
+					depth--
+				}
+			}
+		}
+	}
+}
+
+func unquote(s string) string {
+	if strings.HasPrefix(s, `"""`) {
+		s = strings.TrimPrefix(s, `"""`)
+		s = strings.TrimSuffix(s, `"""`)
+		return strings.TrimSpace(s)
+	}
+	s = strings.TrimPrefix(s, `"`)
+	s = strings.TrimSuffix(s, `"`)
+
+	// Unescape left to right, one escape sequence at a time, so that e.g.
+	// an escaped backslash followed by a literal "n" isn't mistaken for
+	// an escaped newline. Sequential strings.ReplaceAll passes (one per
+	// escape kind) would get this wrong because the earlier passes can't
+	// tell a literal backslash from one that's part of a later escape.
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case '"':
+				b.WriteByte('"')
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case '\\':
+				b.WriteByte('\\')
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(s[i])
+			}
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,27 @@
+package sdl
+
+import "testing"
+
+// TestUnquote checks the escape sequences a description string may contain,
+// including the tricky case of an escaped backslash immediately followed by
+// a literal "n" or "t": that must not be read as an escaped newline/tab.
+func TestUnquote(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`"hello"`, "hello"},
+		{`"a\"b"`, `a"b`},
+		{`"a\nb"`, "a\nb"},
+		{`"a\tb"`, "a\tb"},
+		{`"a\\b"`, `a\b`},
+		{`"a\\nb"`, "a\\nb"},
+		{`"""block"""`, "block"},
+	}
+	for _, tt := range tests {
+		got := unquote(tt.in)
+		if got != tt.want {
+			t.Errorf("unquote(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}